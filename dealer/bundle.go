@@ -0,0 +1,152 @@
+package dealer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	eckeygen "github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/nacl/box"
+)
+
+// RecipientPubKey and RecipientPrivKey are an operator's NaCl box (X25519)
+// key pair, used to seal the operator's share in a bundle.
+type (
+	RecipientPubKey  [32]byte
+	RecipientPrivKey [32]byte
+)
+
+// ParseRecipientPubKey decodes a public key in the base64 form String
+// prints.
+func ParseRecipientPubKey(s string) (RecipientPubKey, error) {
+	var pub RecipientPubKey
+	bz, err := base64.StdEncoding.DecodeString(s)
+	if err != nil || len(bz) != len(pub) {
+		return pub, fmt.Errorf("%w: recipient key must be %d base64-encoded bytes", ErrInvalidConfig, len(pub))
+	}
+	copy(pub[:], bz)
+	return pub, nil
+}
+
+// String encodes the public key as base64.
+func (k RecipientPubKey) String() string {
+	return base64.StdEncoding.EncodeToString(k[:])
+}
+
+// ParseRecipientPrivKey decodes a private key given as 32 base64-encoded
+// bytes, ignoring surrounding whitespace so it can be read from a file.
+func ParseRecipientPrivKey(s string) (RecipientPrivKey, error) {
+	var priv RecipientPrivKey
+	bz, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	defer wipeBytes(bz)
+	if err != nil || len(bz) != len(priv) {
+		return priv, fmt.Errorf("%w: recipient private key must be %d base64-encoded bytes", ErrInvalidConfig, len(priv))
+	}
+	copy(priv[:], bz)
+	return priv, nil
+}
+
+// publicKey derives the public key of the pair.
+func (k RecipientPrivKey) publicKey() (RecipientPubKey, error) {
+	var pub RecipientPubKey
+	bz, err := curve25519.X25519(k[:], curve25519.Basepoint)
+	if err != nil {
+		return pub, fmt.Errorf("invalid recipient key: %v", err)
+	}
+	copy(pub[:], bz)
+	return pub, nil
+}
+
+// bundleVersion is bumped whenever the bundle layout changes.
+const bundleVersion = 1
+
+// shareBundle is the on-disk bundle: one sealed share per recipient.
+type shareBundle struct {
+	Version int           `json:"version"`
+	Shares  []sealedShare `json:"shares"`
+}
+
+type sealedShare struct {
+	Recipient []byte `json:"recipient"`
+	Box       []byte `json:"box"`
+}
+
+// GenerateRecipientKey creates a key pair an operator can receive shares
+// with.
+func GenerateRecipientKey() (RecipientPubKey, RecipientPrivKey, error) {
+	pub, priv, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		return RecipientPubKey{}, RecipientPrivKey{}, err
+	}
+	return RecipientPubKey(*pub), RecipientPrivKey(*priv), nil
+}
+
+// SealBundle packs the shares into one bundle, sealing results[i] to
+// recipients[i] with an anonymous NaCl box. Each operator can open only
+// their own share from it, with OpenFromBundle.
+func SealBundle(results []eckeygen.LocalPartySaveData, recipients []RecipientPubKey) ([]byte, error) {
+	if len(results) != len(recipients) {
+		return nil, fmt.Errorf("%w: %d shares but %d recipients", ErrInvalidConfig, len(results), len(recipients))
+	}
+	seen := make(map[RecipientPubKey]bool, len(recipients))
+	bundle := shareBundle{Version: bundleVersion, Shares: make([]sealedShare, len(results))}
+	for i := range results {
+		pub := recipients[i]
+		if seen[pub] {
+			return nil, fmt.Errorf("%w: recipient %d is listed twice", ErrInvalidConfig, i)
+		}
+		seen[pub] = true
+
+		bz, err := json.Marshal(results[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize share %d: %v", i, err)
+		}
+		sealed, err := box.SealAnonymous(nil, bz, (*[32]byte)(&pub), rand.Reader)
+		wipeBytes(bz)
+		if err != nil {
+			return nil, fmt.Errorf("failed to seal share %d: %v", i, err)
+		}
+		bundle.Shares[i] = sealedShare{Recipient: pub[:], Box: sealed}
+	}
+	return json.MarshalIndent(bundle, "", "  ")
+}
+
+// OpenFromBundle extracts the share sealed to myPriv's public key from a
+// bundle made by SealBundle.
+func OpenFromBundle(bundle []byte, myPriv RecipientPrivKey) (*eckeygen.LocalPartySaveData, error) {
+	var b shareBundle
+	if err := json.Unmarshal(bundle, &b); err != nil {
+		return nil, fmt.Errorf("%w: failed to parse bundle: %v", ErrShareCorrupted, err)
+	}
+	if b.Version != bundleVersion {
+		return nil, fmt.Errorf("%w: unsupported bundle version %d", ErrShareCorrupted, b.Version)
+	}
+	recipient, err := myPriv.publicKey()
+	if err != nil {
+		return nil, err
+	}
+	pub := [32]byte(recipient)
+
+	for _, s := range b.Shares {
+		if !bytes.Equal(s.Recipient, pub[:]) {
+			continue
+		}
+		priv := [32]byte(myPriv)
+		bz, ok := box.OpenAnonymous(nil, s.Box, &pub, &priv)
+		wipeBytes(priv[:])
+		if !ok {
+			return nil, fmt.Errorf("%w: share sealed to this key can't be opened", ErrShareCorrupted)
+		}
+		defer wipeBytes(bz)
+		save := new(eckeygen.LocalPartySaveData)
+		if err := json.Unmarshal(bz, save); err != nil {
+			return nil, fmt.Errorf("%w: failed to parse sealed share: %v", ErrShareCorrupted, err)
+		}
+		return save, nil
+	}
+	return nil, fmt.Errorf("bundle holds no share for this key")
+}
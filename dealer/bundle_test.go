@@ -0,0 +1,90 @@
+package dealer
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// recipientKeys generates n recipient key pairs.
+func recipientKeys(t *testing.T, n int) ([]RecipientPubKey, []RecipientPrivKey) {
+	t.Helper()
+	pubs := make([]RecipientPubKey, n)
+	privs := make([]RecipientPrivKey, n)
+	for i := range pubs {
+		var err error
+		if pubs[i], privs[i], err = GenerateRecipientKey(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return pubs, privs
+}
+
+func TestBundleRoundTrip(t *testing.T) {
+	shares := shamirShares(t, tss.S256(), big.NewInt(42), 1, 1, 2, 3)
+	pubs, privs := recipientKeys(t, len(shares))
+	bundle, err := SealBundle(shares, pubs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, priv := range privs {
+		got, err := OpenFromBundle(bundle, priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.ShareID.Cmp(shares[i].ShareID) != 0 || got.Xi.Cmp(shares[i].Xi) != 0 {
+			t.Errorf("recipient %d opened share %s, want %s", i, got.ShareID, shares[i].ShareID)
+		}
+	}
+}
+
+func TestOpenFromBundleWrongKey(t *testing.T) {
+	shares := shamirShares(t, tss.S256(), big.NewInt(42), 1, 1, 2)
+	pubs, _ := recipientKeys(t, len(shares))
+	bundle, err := SealBundle(shares, pubs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, others := recipientKeys(t, 1)
+	if save, err := OpenFromBundle(bundle, others[0]); err == nil || save != nil {
+		t.Fatalf("opened %v with a key the bundle wasn't sealed to", save)
+	}
+}
+
+func TestOpenFromBundleTampered(t *testing.T) {
+	shares := shamirShares(t, tss.S256(), big.NewInt(42), 1, 1, 2)
+	pubs, privs := recipientKeys(t, len(shares))
+	bundle, err := SealBundle(shares, pubs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b shareBundle
+	if err := json.Unmarshal(bundle, &b); err != nil {
+		t.Fatal(err)
+	}
+	box := b.Shares[1].Box
+	box[len(box)/2] ^= 1
+	if bundle, err = json.Marshal(b); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenFromBundle(bundle, privs[1]); !errors.Is(err, ErrShareCorrupted) {
+		t.Fatalf("got %v, want ErrShareCorrupted", err)
+	}
+	if _, err := OpenFromBundle(bundle, privs[0]); err != nil {
+		t.Fatalf("an untouched share: %v", err)
+	}
+}
+
+func TestSealBundleRejectsBadRecipients(t *testing.T) {
+	shares := shamirShares(t, tss.S256(), big.NewInt(42), 1, 1, 2)
+	pubs, _ := recipientKeys(t, 1)
+	if _, err := SealBundle(shares, pubs); !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("too few recipients: got %v, want ErrInvalidConfig", err)
+	}
+	if _, err := SealBundle(shares, []RecipientPubKey{pubs[0], pubs[0]}); !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("a repeated recipient: got %v, want ErrInvalidConfig", err)
+	}
+}
@@ -0,0 +1,346 @@
+package dealer
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	eckeygen "github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+	"gopkg.in/yaml.v3"
+)
+
+// Scheme selects which threshold signature protocol a key is dealt for.
+type Scheme string
+
+const (
+	SchemeECDSA Scheme = "ecdsa"
+	SchemeEDDSA Scheme = "eddsa"
+)
+
+// PartyConfig describes a single party of the group.
+type PartyConfig struct {
+	ID      string `json:"id" yaml:"id"`
+	Moniker string `json:"moniker" yaml:"moniker"`
+	Index   int64  `json:"index" yaml:"index"`
+}
+
+// ImportConfig describes the group topology a key is dealt to. Threshold
+// follows the tss-lib convention: any Threshold+1 of the PartyCount signers
+// can sign. It must be in [1, PartyCount-1]: t=0 would hand every signer the
+// whole key, and t=PartyCount-1 is the n-of-n extreme.
+type ImportConfig struct {
+	Scheme     Scheme        `json:"scheme" yaml:"scheme"`
+	Curve      string        `json:"curve" yaml:"curve"`
+	Threshold  int           `json:"threshold" yaml:"threshold"`
+	PartyCount int           `json:"party_count" yaml:"party_count"`
+	Importer   PartyConfig   `json:"importer" yaml:"importer"`
+	Parties    []PartyConfig `json:"parties" yaml:"parties"`
+
+	// PreParamsDir caches each party's ECDSA pre-params between runs. Leave
+	// empty to always generate fresh ones.
+	PreParamsDir string `json:"pre_params_dir" yaml:"pre_params_dir"`
+	// OutputDir receives one share file per signer. Leave empty to skip
+	// writing shares.
+	OutputDir string `json:"output_dir" yaml:"output_dir"`
+	// OutputFormat is OutputFiles (the default) or OutputStdout.
+	OutputFormat string `json:"output_format" yaml:"output_format"`
+	// OutputRecipient, when set, is a base64 X25519 public key (see
+	// GenerateRecipientKey) every stdout line is sealed to, so the captured
+	// output can't be read without its private key. Only for OutputStdout.
+	OutputRecipient string `json:"output_recipient" yaml:"output_recipient"`
+	// Store, when set, receives the shares in place of OutputDir, e.g. a
+	// KeyStore backed by a secrets manager. Unlike OutputDir, shares are put
+	// one at a time, so a failed deal may leave some behind.
+	Store KeyStore `json:"-" yaml:"-"`
+	// Force lets a deal replace shares already in OutputDir or Store, e.g.
+	// from an earlier run of the same config. It can't be set from a file.
+	Force bool `json:"-" yaml:"-"`
+
+	// NoProofFac and NoProofMod skip the Paillier factor and modulus proofs
+	// during ECDSA resharing. Only use them when every party is trusted.
+	NoProofFac bool `json:"no_proof_fac" yaml:"no_proof_fac"`
+	NoProofMod bool `json:"no_proof_mod" yaml:"no_proof_mod"`
+
+	// DedupMessages makes the router drop exact duplicates of a message it
+	// already delivered, as a transport that retries may produce.
+	DedupMessages bool `json:"dedup_messages" yaml:"dedup_messages"`
+
+	// duplicateMessages makes the router route every message twice, as a
+	// retrying transport might, to test DedupMessages.
+	duplicateMessages bool
+
+	// Timeout bounds how long the resharing may run before the deal is
+	// abandoned. Zero means defaultResharingTimeout.
+	Timeout time.Duration `json:"timeout" yaml:"timeout"`
+
+	// OldPeerContext and NewPeerContext, when set, are used as the old and
+	// new groups' peer contexts instead of building them from Importer and
+	// Parties, e.g. to match a coordinator's on-wire ordering. They must hold
+	// exactly the configured parties.
+	OldPeerContext *tss.PeerContext `json:"-" yaml:"-"`
+	NewPeerContext *tss.PeerContext `json:"-" yaml:"-"`
+
+	// Deliver, when set, decides whether a message from one party is
+	// delivered to another. It lets robustness tests partition the network
+	// and check the protocol blocks (and times out) instead of completing.
+	Deliver func(from, to *tss.PartyID) bool `json:"-" yaml:"-"`
+
+	// Progress, when set, is called as each party advances to a new round of
+	// the resharing, and once more with round == total when it completes.
+	// It may be called from more than one goroutine.
+	Progress func(partyID string, round, total int) `json:"-" yaml:"-"`
+
+	// PreParamsPool, when set, supplies every party's ECDSA pre-params in
+	// place of generating them inline or reading PreParamsDir.
+	PreParamsPool *PreParamsPool `json:"-" yaml:"-"`
+
+	// Logger, when set, receives the deal's progress and a line for every
+	// message routed between the parties. Nothing secret is logged.
+	Logger *log.Logger `json:"-" yaml:"-"`
+
+	// Recorder, when set, captures every message the router delivers for
+	// later replay with ReplayFrom.
+	Recorder *MessageRecorder `json:"-" yaml:"-"`
+
+	// ReturnGeneratedKey makes GenerateAndDealECDSA hand the freshly minted
+	// private key back instead of wiping it. It can't be set from a file.
+	ReturnGeneratedKey bool `json:"-" yaml:"-"`
+}
+
+// defaultImporter is the old-group party holding the plaintext key.
+var defaultImporter = PartyConfig{ID: "importer", Moniker: "Importer", Index: 0}
+
+// DefaultImportConfig returns the built-in demo group: the importer deals an
+// ed25519 key to three signers, all of whom are needed to sign (t+1=3 ⇒ t=2).
+func DefaultImportConfig() *ImportConfig {
+	return &ImportConfig{
+		Scheme:     SchemeEDDSA,
+		Curve:      CurveEd25519,
+		Threshold:  2,
+		PartyCount: 3,
+		Importer:   defaultImporter,
+		Parties: []PartyConfig{
+			{ID: "signer1", Moniker: "Signer1", Index: 1},
+			{ID: "signer2", Moniker: "Signer2", Index: 2},
+			{ID: "signer3", Moniker: "Signer3", Index: 3},
+		},
+	}
+}
+
+// LoadConfig reads a YAML (or JSON) group config from path and validates it.
+// The file must end in .yaml, .yml or .json; JSON is read as the YAML
+// subset it is. The importer defaults to the demo importer when
+// the file doesn't set one.
+func LoadConfig(path string) (*ImportConfig, error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml", ".json":
+	default:
+		return nil, fmt.Errorf("%w: config %s has unsupported extension %q, want .yaml, .yml or .json", ErrInvalidConfig, path, ext)
+	}
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &ImportConfig{Importer: defaultImporter}
+	dec := yaml.NewDecoder(bytes.NewReader(bz))
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil {
+		return nil, fmt.Errorf("%w: failed to parse config %s: %v", ErrInvalidConfig, path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// MaxPartyCount caps PartyCount. Every round queues O(n²) messages in memory
+// and each party verifies O(n) proofs per round, so larger groups are better
+// dealt with a networked transport.
+const MaxPartyCount = 256
+
+// Validate checks the config is a usable group topology without doing any
+// expensive work, so a bad config fails before pre-params are generated.
+func (cfg *ImportConfig) Validate() error {
+	if err := checkSchemeCurve(cfg.Scheme, cfg.Curve); err != nil {
+		return err
+	}
+	if _, err := cfg.curve(); err != nil {
+		return err
+	}
+	if cfg.OutputFormat != "" && cfg.OutputFormat != OutputFiles && cfg.OutputFormat != OutputStdout {
+		return fmt.Errorf("%w: unknown output format %q", ErrInvalidConfig, cfg.OutputFormat)
+	}
+	if cfg.OutputRecipient != "" {
+		if cfg.OutputFormat != OutputStdout {
+			return fmt.Errorf("%w: output_recipient only applies to the %s output format", ErrInvalidConfig, OutputStdout)
+		}
+		if _, err := cfg.outputRecipient(); err != nil {
+			return err
+		}
+	}
+	if cfg.PartyCount != len(cfg.Parties) {
+		return fmt.Errorf("%w: party_count is %d but %d parties are listed", ErrInvalidConfig, cfg.PartyCount, len(cfg.Parties))
+	}
+	if cfg.PartyCount > MaxPartyCount {
+		return fmt.Errorf("%w: party_count %d is above the %d the in-process transport supports",
+			ErrInvalidConfig, cfg.PartyCount, MaxPartyCount)
+	}
+	if cfg.Threshold < 1 {
+		return fmt.Errorf("%w: threshold %d lets a single signer sign alone, it must be at least 1",
+			ErrInvalidConfig, cfg.Threshold)
+	}
+	if cfg.Threshold >= cfg.PartyCount {
+		return fmt.Errorf("%w: threshold %d needs %d signers but only %d are configured",
+			ErrThresholdTooHigh, cfg.Threshold, cfg.Threshold+1, cfg.PartyCount)
+	}
+	if cfg.Timeout < 0 {
+		return fmt.Errorf("%w: timeout %s is negative", ErrInvalidConfig, cfg.Timeout)
+	}
+	if cfg.Importer.ID == "" {
+		return fmt.Errorf("%w: importer has no id", ErrInvalidConfig)
+	}
+	if cfg.Importer.Index < 0 {
+		return fmt.Errorf("%w: importer %s has negative index %d", ErrInvalidConfig, cfg.Importer.ID, cfg.Importer.Index)
+	}
+
+	ids := map[string]bool{}
+	indexes := map[int64]string{}
+	if !cfg.importerIsSigner() {
+		ids[cfg.Importer.ID] = true
+		indexes[cfg.Importer.Index] = cfg.Importer.ID
+	}
+	for _, p := range cfg.Parties {
+		if p.ID == "" {
+			return fmt.Errorf("%w: party with index %d has no id", ErrInvalidConfig, p.Index)
+		}
+		if p.ID == ManifestID {
+			return fmt.Errorf("%w: party id %s is reserved for the deal's manifest", ErrInvalidConfig, p.ID)
+		}
+		if ids[p.ID] {
+			return fmt.Errorf("%w: duplicate party id %s", ErrInvalidConfig, p.ID)
+		}
+		ids[p.ID] = true
+		// A share evaluated at zero would be the secret itself.
+		if p.Index <= 0 {
+			return fmt.Errorf("%w: party %s has non-positive index %d", ErrInvalidConfig, p.ID, p.Index)
+		}
+		if other, ok := indexes[p.Index]; ok {
+			return fmt.Errorf("%w: parties %s and %s share index %d", ErrDuplicatePartyIndex, other, p.ID, p.Index)
+		}
+		indexes[p.Index] = p.ID
+	}
+	if cfg.importerIsSigner() {
+		return cfg.validateImporterAsSigner()
+	}
+	return nil
+}
+
+// importerIsSigner reports whether the importer stays on in the new group,
+// i.e. it is also listed among the parties.
+func (cfg *ImportConfig) importerIsSigner() bool {
+	for _, p := range cfg.Parties {
+		if p.ID == cfg.Importer.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// validateImporterAsSigner checks an importer that stays on in the new group
+// can be run as a single party in both groups. tss-lib tells the groups'
+// members apart by key and addresses a party by its position in each group,
+// so the importer must keep its index and be first in the new group, just as
+// it is first (and only) in the old one.
+func (cfg *ImportConfig) validateImporterAsSigner() error {
+	for _, p := range cfg.Parties {
+		if p.ID == cfg.Importer.ID && p.Index != cfg.Importer.Index {
+			return fmt.Errorf("%w: importer %s must keep index %d in the new group, not %d",
+				ErrInvalidConfig, p.ID, cfg.Importer.Index, p.Index)
+		}
+		if p.Index < cfg.Importer.Index {
+			return fmt.Errorf("%w: importer %s must have the lowest index in the new group, but %s has %d",
+				ErrInvalidConfig, cfg.Importer.ID, p.ID, p.Index)
+		}
+	}
+	return nil
+}
+
+// timeout is the configured resharing timeout, or the default.
+func (cfg *ImportConfig) timeout() time.Duration {
+	if cfg.Timeout > 0 {
+		return cfg.Timeout
+	}
+	return defaultResharingTimeout
+}
+
+// logf writes a line to cfg's Logger, if it has one.
+func (cfg *ImportConfig) logf(format string, args ...interface{}) {
+	if cfg.Logger != nil {
+		cfg.Logger.Printf(format, args...)
+	}
+}
+
+// reportDone tells the progress callback that partyID finished every round.
+func (cfg *ImportConfig) reportDone(partyID string) {
+	if cfg.Progress != nil {
+		total := ExpectedRounds(cfg.Scheme)
+		cfg.Progress(partyID, total, total)
+	}
+}
+
+// outputRecipient decodes OutputRecipient, or returns nil when it isn't set.
+func (cfg *ImportConfig) outputRecipient() (*RecipientPubKey, error) {
+	if cfg.OutputRecipient == "" {
+		return nil, nil
+	}
+	pub, err := ParseRecipientPubKey(cfg.OutputRecipient)
+	if err != nil {
+		return nil, fmt.Errorf("output_recipient: %w", err)
+	}
+	return &pub, nil
+}
+
+// preParams returns the ECDSA pre-params for partyID, from the pool when one
+// is configured and from the cache in PreParamsDir otherwise.
+func (cfg *ImportConfig) preParams(partyID string) (*eckeygen.LocalPreParams, error) {
+	if cfg.PreParamsPool != nil {
+		pre := cfg.PreParamsPool.Get()
+		if pre == nil {
+			return nil, fmt.Errorf("pre-params pool is closed")
+		}
+		if err := CheckPreParamsSecurity(pre); err != nil {
+			return nil, fmt.Errorf("pooled pre-params: %w", err)
+		}
+		return pre, nil
+	}
+	return loadOrGeneratePreParams(cfg.PreParamsDir, partyID)
+}
+
+// curve resolves the configured curve name against the curves tss-lib knows.
+func (cfg *ImportConfig) curve() (elliptic.Curve, error) {
+	curve, ok := tss.GetCurveByName(tss.CurveName(cfg.Curve))
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown curve %q", ErrInvalidConfig, cfg.Curve)
+	}
+	return curve, nil
+}
+
+// signerPartyIDs builds the new group's party IDs in config order.
+func (cfg *ImportConfig) signerPartyIDs() []*tss.PartyID {
+	pids := make([]*tss.PartyID, len(cfg.Parties))
+	for i, p := range cfg.Parties {
+		pids[i] = p.partyID()
+	}
+	return pids
+}
+
+func (p PartyConfig) partyID() *tss.PartyID {
+	return tss.NewPartyID(p.ID, p.Moniker, big.NewInt(p.Index))
+}
@@ -0,0 +1,127 @@
+package dealer
+
+import (
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	edkeygen "github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+)
+
+func TestValidateThresholdRange(t *testing.T) {
+	for _, tc := range []struct {
+		threshold, n int
+		want         error
+	}{
+		{0, 3, ErrInvalidConfig},
+		{-1, 3, ErrInvalidConfig},
+		{1, 3, nil},
+		{2, 3, nil},
+		{3, 3, ErrThresholdTooHigh},
+		{1, 2, nil},
+	} {
+		err := testConfig(SchemeEDDSA, tc.threshold, tc.n).Validate()
+		if !errors.Is(err, tc.want) {
+			t.Errorf("t=%d n=%d: got %v, want %v", tc.threshold, tc.n, err, tc.want)
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	const yamlConfig = `scheme: eddsa
+curve: ed25519
+threshold: 1
+party_count: 2
+timeout: 30s
+parties:
+  - {id: alice, moniker: Alice, index: 1}
+  - {id: bob, moniker: Bob, index: 2}
+`
+	const jsonConfig = `{
+  "scheme": "eddsa",
+  "curve": "ed25519",
+  "threshold": 1,
+  "party_count": 2,
+  "timeout": "30s",
+  "parties": [
+    {"id": "alice", "moniker": "Alice", "index": 1},
+    {"id": "bob", "moniker": "Bob", "index": 2}
+  ]
+}
+`
+	for _, tc := range []struct {
+		file, contents string
+		want           error
+	}{
+		{"group.yaml", yamlConfig, nil},
+		{"group.yml", yamlConfig, nil},
+		{"group.json", jsonConfig, nil},
+		{"unknown.yaml", yamlConfig + "thresold: 2\n", ErrInvalidConfig},
+		{"unknown.json", `{"scheme": "eddsa", "curve": "ed25519", "thresold": 1}`, ErrInvalidConfig},
+		{"group.toml", yamlConfig, ErrInvalidConfig},
+		{"group", yamlConfig, ErrInvalidConfig},
+		{"invalid.yaml", "scheme: eddsa\ncurve: ed25519\nthreshold: 0\nparty_count: 0\n", ErrInvalidConfig},
+	} {
+		t.Run(tc.file, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tc.file)
+			if err := os.WriteFile(path, []byte(tc.contents), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := LoadConfig(path)
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("got %v, want %v", err, tc.want)
+			}
+			if tc.want != nil {
+				return
+			}
+			if cfg.Scheme != SchemeEDDSA || cfg.Curve != CurveEd25519 || cfg.Threshold != 1 || cfg.Timeout != 30*time.Second {
+				t.Errorf("loaded %+v", cfg)
+			}
+			if len(cfg.Parties) != 2 || cfg.Parties[1] != (PartyConfig{ID: "bob", Moniker: "Bob", Index: 2}) {
+				t.Errorf("loaded parties %+v", cfg.Parties)
+			}
+			if cfg.Importer != defaultImporter {
+				t.Errorf("importer is %+v, want the default", cfg.Importer)
+			}
+		})
+	}
+}
+
+// checkQuorumSize deals a key to cfg's group and checks any t+1 shares
+// reconstruct it while t don't.
+func checkQuorumSize(t *testing.T, cfg *ImportConfig) {
+	t.Helper()
+	key := testKey(t, cfg)
+	want := new(big.Int).Set(key)
+	res, err := ImportEdDSAKey(cfg, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares := res.EdDSAShares
+	quorum := cfg.Threshold + 1
+	for _, subset := range [][]edkeygen.LocalPartySaveData{shares[:quorum], shares[len(shares)-quorum:]} {
+		got, err := ReconstructEdDSAKey(subset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Cmp(want) != 0 {
+			t.Fatal("a quorum reconstructs a different key")
+		}
+	}
+	if _, err := ReconstructEdDSAKey(shares[:quorum-1]); !errors.Is(err, ErrShareCorrupted) {
+		t.Fatalf("%d shares: got %v, want ErrShareCorrupted", quorum-1, err)
+	}
+}
+
+func TestNOfN(t *testing.T) {
+	skipIfShort(t)
+	checkQuorumSize(t, testConfig(SchemeEDDSA, 2, 3))
+}
+
+func TestSmallestQuorum(t *testing.T) {
+	skipIfShort(t)
+	checkQuorumSize(t, testConfig(SchemeEDDSA, 1, 3))
+}
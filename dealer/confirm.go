@@ -0,0 +1,47 @@
+package dealer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	tsscrypto "github.com/bnb-chain/tss-lib/v2/crypto"
+)
+
+// ConfirmDeal shows the operator the key about to be dealt and the group it
+// goes to, and only returns nil once they type "yes". Dealing is
+// irreversible, so anything else aborts.
+func ConfirmDeal(cfg *ImportConfig, key *big.Int, in io.Reader, out io.Writer) error {
+	curve, err := cfg.curve()
+	if err != nil {
+		return err
+	}
+	if !InRange(key, curve) {
+		return fmt.Errorf("%w: key must be in [1, N-1] for curve %s", ErrInvalidKeyRange, cfg.Curve)
+	}
+	pub := tsscrypto.ScalarBaseMult(curve, key)
+
+	fmt.Fprintf(out, "About to deal a %s key on %s:\n", cfg.Scheme, cfg.Curve)
+	fmt.Fprintf(out, "  public key: (%s, %s)\n", pub.X(), pub.Y())
+	if cfg.Curve == CurveSecp256k1 {
+		fmt.Fprintf(out, "  address:    %s\n", ethereumAddress(pub))
+	}
+	fmt.Fprintf(out, "  importer:   %s (index %d)\n", cfg.Importer.ID, cfg.Importer.Index)
+	fmt.Fprintf(out, "  signers:    %d, any %d of which can sign (threshold %d)\n",
+		cfg.PartyCount, cfg.Threshold+1, cfg.Threshold)
+	for _, p := range cfg.Parties {
+		fmt.Fprintf(out, "    %s %q (index %d)\n", p.ID, p.Moniker, p.Index)
+	}
+	fmt.Fprint(out, "Type \"yes\" to deal this key: ")
+
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return err
+	}
+	if strings.TrimSpace(answer) != "yes" {
+		return fmt.Errorf("deal not confirmed")
+	}
+	return nil
+}
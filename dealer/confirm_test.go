@@ -0,0 +1,59 @@
+package dealer
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	tsscrypto "github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestConfirmDeal(t *testing.T) {
+	cfg := testConfig(SchemeECDSA, 1, 3)
+	key := big.NewInt(0xdea1)
+	pub := tsscrypto.ScalarBaseMult(tss.S256(), key)
+	for _, tc := range []struct {
+		name, input string
+		confirmed   bool
+	}{
+		{"yes", "yes\n", true},
+		{"yes at EOF", "yes", true},
+		{"padded yes", "  yes \r\n", true},
+		{"y", "y\n", false},
+		{"n", "n\n", false},
+		{"empty line", "\n", false},
+		{"EOF", "", false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var out strings.Builder
+			err := ConfirmDeal(cfg, key, strings.NewReader(tc.input), &out)
+			if confirmed := err == nil; confirmed != tc.confirmed {
+				t.Fatalf("got %v, want confirmed %v", err, tc.confirmed)
+			}
+			prompt := out.String()
+			for _, want := range []string{
+				pub.X().String(), pub.Y().String(), ethereumAddress(pub),
+				"importer (index 0)", "any 2 of which can sign (threshold 1)",
+				`signer1 "Signer1" (index 1)`, `signer3 "Signer3" (index 3)`,
+			} {
+				if !strings.Contains(prompt, want) {
+					t.Errorf("the prompt doesn't show %q:\n%s", want, prompt)
+				}
+			}
+		})
+	}
+}
+
+func TestConfirmDealRejectsOutOfRangeKey(t *testing.T) {
+	cfg := testConfig(SchemeECDSA, 1, 3)
+	var out strings.Builder
+	err := ConfirmDeal(cfg, big.NewInt(0), strings.NewReader("yes\n"), &out)
+	if !errors.Is(err, ErrInvalidKeyRange) {
+		t.Fatalf("got %v, want ErrInvalidKeyRange", err)
+	}
+	if out.Len() != 0 {
+		t.Fatalf("prompted for an invalid key:\n%s", out.String())
+	}
+}
@@ -0,0 +1,104 @@
+package dealer
+
+import (
+	"crypto/elliptic"
+	"fmt"
+	"math/big"
+	"strings"
+
+	tsscrypto "github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// Curve names accepted in the config.
+const (
+	CurveSecp256k1 = string(tss.Secp256k1)
+	CurveP256      = "p256"
+	CurveP384      = "p384"
+	CurveEd25519   = string(tss.Ed25519)
+)
+
+// tss-lib only registers secp256k1 and ed25519 itself; the NIST curves need
+// registering so they resolve by name and their points serialize.
+func init() {
+	tss.RegisterCurve(tss.CurveName(CurveP256), elliptic.P256())
+	tss.RegisterCurve(tss.CurveName(CurveP384), elliptic.P384())
+}
+
+// schemeCurves lists the curves each scheme can be dealt on.
+var schemeCurves = map[Scheme][]string{
+	SchemeECDSA: {CurveSecp256k1, CurveP256, CurveP384},
+	SchemeEDDSA: {CurveEd25519},
+}
+
+// checkSchemeCurve rejects nonsensical pairings such as an EDDSA key on
+// secp256k1.
+func checkSchemeCurve(scheme Scheme, curve string) error {
+	allowed, ok := schemeCurves[scheme]
+	if !ok {
+		return fmt.Errorf("%w: unknown scheme %q", ErrInvalidConfig, scheme)
+	}
+	for _, c := range allowed {
+		if c == curve {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: curve %q cannot be used with scheme %s (allowed: %s)",
+		ErrInvalidConfig, curve, scheme, strings.Join(allowed, ", "))
+}
+
+// InRange reports whether key is a usable private scalar on curve, i.e. in
+// [1, N-1] for the curve's group order N. For ed25519 tss-lib's N is the
+// prime subgroup order L, so the same check applies to EDDSA scalars.
+func InRange(key *big.Int, curve elliptic.Curve) bool {
+	return key != nil && key.Sign() > 0 && key.Cmp(curve.Params().N) < 0
+}
+
+// CheckShareCurve makes sure the share stored under id was dealt for the
+// scheme and curve cfg asks for. Resharing a key onto another curve or
+// scheme is meaningless, so this runs before any work is done.
+func CheckShareCurve(store KeyStore, id string, cfg *ImportConfig) error {
+	info, err := InspectShare(store, id)
+	if err != nil {
+		return err
+	}
+	if err := checkShareCurve(info.Scheme, info.PublicKey, cfg.Scheme, cfg.Curve); err != nil {
+		return fmt.Errorf("share %s: %w", id, err)
+	}
+	return nil
+}
+
+// checkShareCurve makes sure save data of scheme with public key pub is for
+// wantScheme on wantCurve.
+func checkShareCurve(scheme Scheme, pub *tsscrypto.ECPoint, wantScheme Scheme, wantCurve string) error {
+	if pub == nil {
+		return fmt.Errorf("%w: shares have no public key", ErrShareCorrupted)
+	}
+	name, ok := tss.GetCurveName(pub.Curve())
+	if !ok {
+		return fmt.Errorf("%w: shares use an unknown curve", ErrCurveMismatch)
+	}
+	if target, ok := tss.GetCurveByName(tss.CurveName(wantCurve)); ok {
+		if err := checkReshareCompatible(pub.Curve(), target); err != nil {
+			return fmt.Errorf("%w: shares are on %s but %s was requested: %w",
+				ErrCurveMismatch, name, wantCurve, err)
+		}
+	}
+	if scheme != wantScheme || string(name) != wantCurve {
+		return fmt.Errorf("%w: shares are %s on %s but %s on %s was requested",
+			ErrCurveMismatch, scheme, name, wantScheme, wantCurve)
+	}
+	return nil
+}
+
+// checkReshareCompatible rejects moving a key from source to a target curve
+// of a different order. A secp256k1 key can't be "converted" to p256 by
+// resharing: the scalar is reduced in another field and no longer the same
+// key, if it is a valid one at all.
+func checkReshareCompatible(source, target elliptic.Curve) error {
+	if source.Params().N.Cmp(target.Params().N) != 0 {
+		return fmt.Errorf("%w: curve orders differ, so the key's scalar has no meaning on the target curve",
+			ErrIncompatibleReshare)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package dealer
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// storedGroup stores a secp256k1 share for each of cfg's signers.
+func storedGroup(t *testing.T, cfg *ImportConfig) KeyStore {
+	t.Helper()
+	store := NewMemoryKeyStore()
+	ids := make([]int64, len(cfg.Parties))
+	for i, p := range cfg.Parties {
+		ids[i] = p.Index
+	}
+	for i, s := range shamirShares(t, tss.S256(), big.NewInt(0xc0ffee), cfg.Threshold, ids...) {
+		if err := SaveShareTo(store, cfg.Parties[i].ID, s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return store
+}
+
+func TestReplaceStoredSignerRejectsAnotherScheme(t *testing.T) {
+	store := storedGroup(t, testConfig(SchemeECDSA, 1, 3))
+	cfg := testConfig(SchemeEDDSA, 1, 3)
+	err := ReplaceStoredSigner(cfg, store, "signer2", PartyConfig{ID: "signer4"})
+	if !errors.Is(err, ErrCurveMismatch) {
+		t.Fatalf("got %v, want ErrCurveMismatch", err)
+	}
+	for _, want := range []string{"secp256k1", "ed25519"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("%q doesn't name %s", err, want)
+		}
+	}
+}
+
+func TestReshareSecp256k1ToP256FailsFast(t *testing.T) {
+	store := storedGroup(t, testConfig(SchemeECDSA, 1, 3))
+	cfg := testConfig(SchemeECDSA, 1, 3)
+	cfg.Curve = CurveP256
+	// Pre-params would be generated into a directory that can't exist, so
+	// reaching that step fails differently.
+	cfg.PreParamsDir = "/dev/null/preparams"
+	err := ReplaceStoredSigner(cfg, store, "signer2", PartyConfig{ID: "signer4"})
+	if !errors.Is(err, ErrIncompatibleReshare) || !errors.Is(err, ErrCurveMismatch) {
+		t.Fatalf("got %v, want ErrIncompatibleReshare", err)
+	}
+	for _, want := range []string{"secp256k1", "p256", "curve orders differ"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("%q doesn't mention %s", err, want)
+		}
+	}
+}
+
+func TestCheckSchemeCurve(t *testing.T) {
+	for _, tc := range []struct {
+		scheme Scheme
+		curve  string
+		want   error
+	}{
+		{SchemeECDSA, CurveSecp256k1, nil},
+		{SchemeECDSA, CurveP256, nil},
+		{SchemeECDSA, CurveP384, nil},
+		{SchemeEDDSA, CurveEd25519, nil},
+		{SchemeECDSA, CurveEd25519, ErrInvalidConfig},
+		{SchemeEDDSA, CurveSecp256k1, ErrInvalidConfig},
+		{SchemeEDDSA, CurveP256, ErrInvalidConfig},
+		{SchemeECDSA, "", ErrInvalidConfig},
+		{SchemeECDSA, "p521", ErrInvalidConfig},
+		{"schnorr", CurveSecp256k1, ErrInvalidConfig},
+	} {
+		if err := checkSchemeCurve(tc.scheme, tc.curve); !errors.Is(err, tc.want) {
+			t.Errorf("%s on %q: got %v, want %v", tc.scheme, tc.curve, err, tc.want)
+		}
+	}
+}
+
+func TestCheckReshareCompatible(t *testing.T) {
+	p256, _ := tss.GetCurveByName(tss.CurveName(CurveP256))
+	if err := checkReshareCompatible(tss.S256(), p256); !errors.Is(err, ErrIncompatibleReshare) {
+		t.Fatalf("secp256k1 to p256: got %v, want ErrIncompatibleReshare", err)
+	}
+	if err := checkReshareCompatible(tss.S256(), tss.S256()); err != nil {
+		t.Fatalf("secp256k1 to itself: %v", err)
+	}
+}
+
+func TestInRange(t *testing.T) {
+	curves := map[string]elliptic.Curve{
+		CurveSecp256k1: tss.S256(),
+		CurveP256:      elliptic.P256(),
+		CurveP384:      elliptic.P384(),
+		CurveEd25519:   tss.Edwards(),
+	}
+	for name, curve := range curves {
+		n := curve.Params().N
+		for _, tc := range []struct {
+			key  *big.Int
+			want bool
+		}{
+			{nil, false},
+			{big.NewInt(-1), false},
+			{big.NewInt(0), false},
+			{big.NewInt(1), true},
+			{new(big.Int).Sub(n, big.NewInt(1)), true},
+			{new(big.Int).Set(n), false},
+			{new(big.Int).Add(n, big.NewInt(1)), false},
+		} {
+			if got := InRange(tc.key, curve); got != tc.want {
+				t.Errorf("%s: InRange(%v) = %v, want %v", name, tc.key, got, tc.want)
+			}
+		}
+	}
+	// tss-lib's ed25519 order is the prime subgroup order L, not the order
+	// of the whole curve.
+	l, _ := new(big.Int).SetString("7237005577332262213973186563042994240857116359379907606001950938285454250989", 10)
+	if tss.Edwards().Params().N.Cmp(l) != 0 {
+		t.Error("ed25519 scalars aren't checked against L")
+	}
+}
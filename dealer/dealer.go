@@ -0,0 +1,110 @@
+// Package dealer deals private keys to tss-lib signer groups: it reshares a
+// key from a 1-of-1 importer group to the configured signers, and maintains
+// the resulting share sets.
+package dealer
+
+import (
+	"math/big"
+	"sync"
+)
+
+// Dealer runs deals for one config and owns what they share: the config's
+// pre-params pool and the goroutines of any deal in progress. Close must be
+// called once the Dealer is no longer needed, or the pool's refiller and the
+// goroutines of an abandoned deal leak.
+type Dealer struct {
+	cfg *ImportConfig
+
+	mu     sync.Mutex
+	closed bool
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewDealer returns a Dealer for cfg. The Dealer takes ownership of
+// cfg.PreParamsPool, if set, and closes it on Close.
+func NewDealer(cfg *ImportConfig) *Dealer {
+	return &Dealer{cfg: cfg, done: make(chan struct{})}
+}
+
+// begin registers a deal, failing once the Dealer is closed.
+func (d *Dealer) begin() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return ErrClosed
+	}
+	d.wg.Add(1)
+	return nil
+}
+
+// ImportECDSAKey is the package-level ImportECDSAKey, run by d.
+func (d *Dealer) ImportECDSAKey(plaintextKey *big.Int) (*ImportResult, error) {
+	if err := d.begin(); err != nil {
+		return nil, err
+	}
+	defer d.wg.Done()
+	return importECDSAKey(d.cfg, plaintextKey, nil, d.done)
+}
+
+// ImportEdDSAKey is the package-level ImportEdDSAKey, run by d.
+func (d *Dealer) ImportEdDSAKey(plaintextKey *big.Int) (*ImportResult, error) {
+	if err := d.begin(); err != nil {
+		return nil, err
+	}
+	defer d.wg.Done()
+	return importEdDSAKey(d.cfg, plaintextKey, nil, d.done)
+}
+
+// ImportEdDSASeed is the package-level ImportEdDSASeed, run by d.
+func (d *Dealer) ImportEdDSASeed(seed []byte) (*ImportResult, error) {
+	if err := d.begin(); err != nil {
+		return nil, err
+	}
+	defer d.wg.Done()
+	return importEdDSASeed(d.cfg, seed, d.done)
+}
+
+// ImportECDSAKeyStream is the package-level ImportECDSAKeyStream, run by d.
+func (d *Dealer) ImportECDSAKeyStream(plaintextKey *big.Int) (<-chan SignerResult, <-chan error) {
+	if err := d.begin(); err != nil {
+		resultCh := make(chan SignerResult)
+		errCh := make(chan error, 1)
+		close(resultCh)
+		errCh <- err
+		close(errCh)
+		return resultCh, errCh
+	}
+	return importECDSAKeyStream(d.cfg, plaintextKey, d.done, d.wg.Done)
+}
+
+// GenerateAndDealECDSA is the package-level GenerateAndDealECDSA, run by d.
+func (d *Dealer) GenerateAndDealECDSA() (*ImportResult, error) {
+	if err := d.begin(); err != nil {
+		return nil, err
+	}
+	defer d.wg.Done()
+	return generateAndDealECDSA(*d.cfg, d.done)
+}
+
+// Close stops d: deals still running give up with ErrClosed, the pre-params
+// pool is closed, and Close returns once every deal has returned and its
+// goroutines have stopped. A deal waiting on a safe-prime search finishes
+// that search first. Calling Close more than once is safe.
+func (d *Dealer) Close() error {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return nil
+	}
+	d.closed = true
+	close(d.done)
+	d.mu.Unlock()
+
+	var err error
+	if d.cfg.PreParamsPool != nil {
+		err = d.cfg.PreParamsPool.Close()
+	}
+	d.wg.Wait()
+	return err
+}
@@ -0,0 +1,191 @@
+package dealer
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	tsscrypto "github.com/bnb-chain/tss-lib/v2/crypto"
+	eckeygen "github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	edkeygen "github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// testPreParamsDir caches ECDSA pre-params across test runs; generating them
+// takes far longer than the resharing itself.
+var testPreParamsDir = filepath.Join(os.TempDir(), "tss-lib-resharing-test-preparams")
+
+// testConfig returns a group of n signers, any threshold+1 of whom can sign,
+// dealt to by the default importer. Shares aren't written anywhere.
+func testConfig(scheme Scheme, threshold, n int) *ImportConfig {
+	cfg := &ImportConfig{
+		Scheme:     scheme,
+		Curve:      CurveEd25519,
+		Threshold:  threshold,
+		PartyCount: n,
+		Importer:   defaultImporter,
+		Timeout:    2 * time.Minute,
+	}
+	if scheme == SchemeECDSA {
+		cfg.Curve = CurveSecp256k1
+		cfg.PreParamsDir = testPreParamsDir
+		cfg.NoProofFac = true
+		cfg.NoProofMod = true
+	}
+	for i := 1; i <= n; i++ {
+		cfg.Parties = append(cfg.Parties, PartyConfig{
+			ID:      fmt.Sprintf("signer%d", i),
+			Moniker: fmt.Sprintf("Signer%d", i),
+			Index:   int64(i),
+		})
+	}
+	return cfg
+}
+
+// testKey returns a random private scalar for cfg's curve.
+func testKey(t *testing.T, cfg *ImportConfig) *big.Int {
+	t.Helper()
+	curve, err := cfg.curve()
+	if err != nil {
+		t.Fatal(err)
+	}
+	k, err := rand.Int(rand.Reader, new(big.Int).Sub(curve.Params().N, big.NewInt(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k.Add(k, big.NewInt(1))
+}
+
+// skipIfShort skips tests that run a full resharing.
+func skipIfShort(t *testing.T) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("runs a full resharing")
+	}
+}
+
+// shamirShares splits key on curve with a random polynomial of the given
+// degree, one ECDSA share per share ID, the way a keygen would leave the
+// public parts. There are no pre-params, so the shares can't be used to
+// sign.
+func shamirShares(t *testing.T, curve elliptic.Curve, key *big.Int, degree int, ids ...int64) []eckeygen.LocalPartySaveData {
+	t.Helper()
+	n := curve.Params().N
+	coeffs := []*big.Int{key}
+	for i := 0; i < degree; i++ {
+		c, err := rand.Int(rand.Reader, n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		coeffs = append(coeffs, c)
+	}
+	ks := make([]*big.Int, len(ids))
+	xis := make([]*big.Int, len(ids))
+	bigXj := make([]*tsscrypto.ECPoint, len(ids))
+	for i, id := range ids {
+		ks[i] = big.NewInt(id)
+		xi := new(big.Int)
+		for j := len(coeffs) - 1; j >= 0; j-- {
+			xi.Mul(xi, ks[i]).Add(xi, coeffs[j]).Mod(xi, n)
+		}
+		xis[i] = xi
+		bigXj[i] = tsscrypto.ScalarBaseMult(curve, xi)
+	}
+	pub := tsscrypto.ScalarBaseMult(curve, key)
+	shares := make([]eckeygen.LocalPartySaveData, len(ids))
+	for i := range ids {
+		s := eckeygen.NewLocalPartySaveData(len(ids))
+		copy(s.Ks, ks)
+		copy(s.BigXj, bigXj)
+		s.LocalSecrets = eckeygen.LocalSecrets{Xi: xis[i], ShareID: ks[i]}
+		s.ECDSAPub = pub
+		shares[i] = s
+	}
+	return shares
+}
+
+// shamirEdShares is shamirShares for EDDSA shares on ed25519.
+func shamirEdShares(t *testing.T, key *big.Int, degree int, ids ...int64) []edkeygen.LocalPartySaveData {
+	t.Helper()
+	var shares []edkeygen.LocalPartySaveData
+	for _, s := range shamirShares(t, tss.Edwards(), key, degree, ids...) {
+		ed := edkeygen.NewLocalPartySaveData(len(s.Ks))
+		copy(ed.Ks, s.Ks)
+		copy(ed.BigXj, s.BigXj)
+		ed.Xi, ed.ShareID, ed.EDDSAPub = s.Xi, s.ShareID, s.ECDSAPub
+		shares = append(shares, ed)
+	}
+	return shares
+}
+
+func TestDealerCloseTwice(t *testing.T) {
+	cfg := testConfig(SchemeEDDSA, 1, 3)
+	cfg.PreParamsPool = newPreParamsPool(1, false)
+	d := NewDealer(cfg)
+	for i := 0; i < 2; i++ {
+		if err := d.Close(); err != nil {
+			t.Fatalf("Close %d: %v", i+1, err)
+		}
+	}
+	if cfg.PreParamsPool.Get() != nil {
+		t.Error("the pool is still open")
+	}
+	if _, err := d.ImportEdDSAKey(testKey(t, cfg)); !errors.Is(err, ErrClosed) {
+		t.Errorf("import on a closed dealer: got %v, want ErrClosed", err)
+	}
+	results, errs := d.ImportECDSAKeyStream(big.NewInt(1))
+	if _, ok := <-results; ok {
+		t.Error("a closed dealer streamed a result")
+	}
+	if err := <-errs; !errors.Is(err, ErrClosed) {
+		t.Errorf("stream on a closed dealer: got %v, want ErrClosed", err)
+	}
+}
+
+func TestDealerCloseCancelsImport(t *testing.T) {
+	cfg := testConfig(SchemeEDDSA, 1, 3)
+	// Nothing is delivered, so the import would wait out its whole timeout.
+	started := make(chan struct{})
+	var once sync.Once
+	cfg.Deliver = func(from, to *tss.PartyID) bool {
+		once.Do(func() { close(started) })
+		return false
+	}
+	d := NewDealer(cfg)
+	errs := make(chan error, 1)
+	go func() {
+		_, err := d.ImportEdDSAKey(testKey(t, cfg))
+		errs <- err
+	}()
+	select {
+	case <-started:
+	case <-time.After(30 * time.Second):
+		t.Fatal("the import never started routing")
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		d.Close()
+		close(closed)
+	}()
+	select {
+	case err := <-errs:
+		if !errors.Is(err, ErrClosed) {
+			t.Fatalf("got %v, want ErrClosed", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Close didn't stop the import")
+	}
+	select {
+	case <-closed:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Close hung")
+	}
+}
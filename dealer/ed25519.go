@@ -0,0 +1,102 @@
+package dealer
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"fmt"
+	"math/big"
+
+	tsscrypto "github.com/bnb-chain/tss-lib/v2/crypto"
+	edkeygen "github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// ImportEdDSASeed deals the key of an Ed25519 seed, as crypto/ed25519 and
+// RFC 8032 derive it, to the signer group described by cfg, and checks the
+// shares against the seed with VerifyEdDSAReconstruction before storing
+// them. The caller's seed is left untouched.
+func ImportEdDSASeed(cfg *ImportConfig, seed []byte) (*ImportResult, error) {
+	return importEdDSASeed(cfg, seed, nil)
+}
+
+// importEdDSASeed is ImportEdDSASeed, giving up with ErrClosed once cancel
+// is closed.
+func importEdDSASeed(cfg *ImportConfig, seed []byte, cancel <-chan struct{}) (*ImportResult, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("%w: ed25519 seed is %d bytes, not %d", ErrInvalidKeyRange, len(seed), ed25519.SeedSize)
+	}
+	key := ed25519SeedScalar(seed)
+	defer wipeBigInt(key)
+	return importEdDSAKey(cfg, key, seed, cancel)
+}
+
+// VerifyEdDSAReconstruction checks EDDSA shares hold the key of an Ed25519
+// seed: a quorum of them must reconstruct the seed's clamped scalar, and its
+// public point must encode to the public key crypto/ed25519 derives from the
+// seed. A key dealt as the seed itself, or unclamped, fails here even though
+// the shares are consistent among themselves.
+func VerifyEdDSAReconstruction(results []edkeygen.LocalPartySaveData, seed []byte) error {
+	if len(seed) != ed25519.SeedSize {
+		return fmt.Errorf("%w: ed25519 seed is %d bytes, not %d", ErrInvalidKeyRange, len(seed), ed25519.SeedSize)
+	}
+	if len(results) == 0 {
+		return fmt.Errorf("no shares to verify")
+	}
+	pub := results[0].EDDSAPub
+	if pub == nil {
+		return fmt.Errorf("%w: shares have no public key", ErrShareCorrupted)
+	}
+	if name, _ := tss.GetCurveName(pub.Curve()); name != tss.Ed25519 {
+		return fmt.Errorf("%w: shares are not on ed25519", ErrShareCorrupted)
+	}
+
+	key, err := ReconstructEdDSAKey(results)
+	if err != nil {
+		return err
+	}
+	defer wipeBigInt(key)
+	expected := ed25519SeedScalar(seed)
+	defer wipeBigInt(expected)
+	if key.Cmp(expected) != 0 {
+		return fmt.Errorf("%w: shares don't reconstruct the seed's scalar", ErrShareCorrupted)
+	}
+
+	seedPub := ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey)
+	if !bytes.Equal(ed25519PointBytes(tsscrypto.ScalarBaseMult(pub.Curve(), key)), seedPub) {
+		return fmt.Errorf("%w: reconstructed public key doesn't match the seed's", ErrShareCorrupted)
+	}
+	return nil
+}
+
+// ed25519SeedScalar expands seed the way RFC 8032 does, hashing it and
+// clamping the lower half, and reduces the result modulo the group order.
+func ed25519SeedScalar(seed []byte) *big.Int {
+	h := sha512.Sum512(seed)
+	defer wipeBytes(h[:])
+	h[0] &= 248
+	h[31] &= 127
+	h[31] |= 64
+	be := make([]byte, 32)
+	defer wipeBytes(be)
+	for i := range be {
+		be[i] = h[31-i]
+	}
+	k := new(big.Int).SetBytes(be)
+	return k.Mod(k, tss.Edwards().Params().N)
+}
+
+// ed25519PointBytes is the RFC 8032 encoding of p: y little-endian, with the
+// parity of x in the top bit.
+func ed25519PointBytes(p *tsscrypto.ECPoint) []byte {
+	var be [32]byte
+	p.Y().FillBytes(be[:])
+	out := make([]byte, 32)
+	for i := range out {
+		out[i] = be[31-i]
+	}
+	if p.X().Bit(0) == 1 {
+		out[31] |= 0x80
+	}
+	return out
+}
@@ -0,0 +1,83 @@
+package dealer
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+
+	tsscrypto "github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// RFC 8032, section 7.1, test 1.
+const (
+	rfc8032Seed   = "9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60"
+	rfc8032Public = "d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a"
+)
+
+func decodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	bz, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bz
+}
+
+func TestEd25519SeedScalar(t *testing.T) {
+	seed := decodeHex(t, rfc8032Seed)
+	scalar := ed25519SeedScalar(seed)
+	got := ed25519PointBytes(tsscrypto.ScalarBaseMult(tss.Edwards(), scalar))
+	if want := decodeHex(t, rfc8032Public); !bytes.Equal(got, want) {
+		t.Fatalf("public key %x, want %x", got, want)
+	}
+	if !bytes.Equal(got, ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey)) {
+		t.Fatal("public key differs from crypto/ed25519's")
+	}
+}
+
+func TestVerifyEdDSAReconstruction(t *testing.T) {
+	seed := decodeHex(t, rfc8032Seed)
+	other := bytes.Repeat([]byte{7}, ed25519.SeedSize)
+	// The seed read as a number is the classic mistake: consistent shares of
+	// the wrong key.
+	unclamped := new(big.Int).Mod(new(big.Int).SetBytes(seed), tss.Edwards().Params().N)
+
+	for _, tc := range []struct {
+		name string
+		key  *big.Int
+		seed []byte
+		want error
+	}{
+		{"seed's scalar", ed25519SeedScalar(seed), seed, nil},
+		{"another seed", ed25519SeedScalar(other), seed, ErrShareCorrupted},
+		{"unclamped seed", unclamped, seed, ErrShareCorrupted},
+		{"short seed", ed25519SeedScalar(seed), seed[:31], ErrInvalidKeyRange},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			shares := shamirEdShares(t, tc.key, 1, 1, 2, 3)
+			if err := VerifyEdDSAReconstruction(shares, tc.seed); !errors.Is(err, tc.want) {
+				t.Fatalf("got %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestImportEdDSASeed(t *testing.T) {
+	skipIfShort(t)
+	cfg := testConfig(SchemeEDDSA, 1, 3)
+	seed := decodeHex(t, rfc8032Seed)
+	res, err := ImportEdDSASeed(cfg, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ed25519PointBytes(res.PublicKey); !bytes.Equal(got, decodeHex(t, rfc8032Public)) {
+		t.Fatalf("dealt public key %x, want the seed's", got)
+	}
+	if !bytes.Equal(seed, decodeHex(t, rfc8032Seed)) {
+		t.Fatal("the caller's seed was changed")
+	}
+}
@@ -0,0 +1,52 @@
+package dealer
+
+import "errors"
+
+// Sentinel errors for the failure classes callers may want to react to.
+// Errors returned by this package wrap them, so test with errors.Is.
+var (
+	// ErrInvalidConfig is returned for a config that can't describe a valid
+	// group, when no more specific error applies.
+	ErrInvalidConfig = errors.New("invalid config")
+	// ErrInvalidKeyRange is returned for a private key outside [1, N-1].
+	ErrInvalidKeyRange = errors.New("key out of range")
+	// ErrDuplicatePartyIndex is returned when two parties share an index.
+	ErrDuplicatePartyIndex = errors.New("duplicate party index")
+	// ErrThresholdTooHigh is returned when the threshold leaves no quorum
+	// among the configured parties.
+	ErrThresholdTooHigh = errors.New("threshold too high")
+	// ErrCurveMismatch is returned when existing shares are for a different
+	// scheme or curve than the operation was asked to use.
+	ErrCurveMismatch = errors.New("curve mismatch")
+	// ErrIncompatibleReshare is returned, along with ErrCurveMismatch, when
+	// existing shares are for a curve of a different order than requested,
+	// so their scalar isn't a valid key on the target curve at all.
+	ErrIncompatibleReshare = errors.New("incompatible reshare")
+	// ErrPreParamsTimeout is returned when pre-params can't be generated in
+	// time.
+	ErrPreParamsTimeout = errors.New("pre-params generation timed out")
+	// ErrWeakPreParams is returned for pre-params below the sizes tss-lib
+	// currently generates, e.g. a cache written by an older version.
+	ErrWeakPreParams = errors.New("pre-params below current security parameters")
+	// ErrParamsMismatch is returned when parties disagree on the resharing
+	// parameters.
+	ErrParamsMismatch = errors.New("resharing parameters mismatch")
+	// ErrResharingTimeout is returned when the importer or a signer doesn't
+	// complete the resharing in time.
+	ErrResharingTimeout = errors.New("resharing timed out")
+	// ErrShareCorrupted is returned when share data is unreadable or doesn't
+	// add up to the key it claims to hold.
+	ErrShareCorrupted = errors.New("share corrupted")
+	// ErrOutputExists is returned when a share file of the target group is
+	// already in the output directory and overwriting wasn't asked for.
+	ErrOutputExists = errors.New("output already exists")
+	// ErrKsMismatch is returned when shares disagree on the group's share
+	// IDs or their order, so their Lagrange coefficients wouldn't line up.
+	ErrKsMismatch = errors.New("share IDs mismatch")
+	// ErrClosed is returned by a Dealer that has been closed, including by
+	// a deal that was still running when it was.
+	ErrClosed = errors.New("dealer closed")
+	// ErrUnknownParty is returned for a message whose sender isn't one of the
+	// group's parties.
+	ErrUnknownParty = errors.New("unknown party")
+)
@@ -0,0 +1,86 @@
+package dealer
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+
+	tsscrypto "github.com/bnb-chain/tss-lib/v2/crypto"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+// GenerateAndDealECDSA mints a fresh ECDSA key on the configured curve and
+// deals it to the signer group. The result carries the new public key (and
+// address) so the operator knows what was created. The private key is wiped
+// once dealt unless cfg.ReturnGeneratedKey is set.
+func GenerateAndDealECDSA(cfg ImportConfig) (*ImportResult, error) {
+	return generateAndDealECDSA(cfg, nil)
+}
+
+// generateAndDealECDSA is GenerateAndDealECDSA, giving up with ErrClosed once
+// cancel is closed.
+func generateAndDealECDSA(cfg ImportConfig, cancel <-chan struct{}) (*ImportResult, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	curve, err := cfg.curve()
+	if err != nil {
+		return nil, err
+	}
+
+	// Uniform in [1, N-1]: draw from [0, N-2] and shift by one.
+	one := big.NewInt(1)
+	max := new(big.Int).Sub(curve.Params().N, one)
+	key, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key: %v", err)
+	}
+	key.Add(key, one)
+	if !InRange(key, curve) {
+		wipeBigInt(key)
+		return nil, fmt.Errorf("%w: generated key for curve %s", ErrInvalidKeyRange, cfg.Curve)
+	}
+
+	// The importer's party consumes (and zeroes) the key it is handed, so
+	// deal a copy and keep key itself for the caller if asked.
+	dealt := new(big.Int).Set(key)
+	result, err := importECDSAKey(&cfg, dealt, nil, cancel)
+	wipeBigInt(dealt)
+	if err != nil || !cfg.ReturnGeneratedKey {
+		wipeBigInt(key)
+	}
+	if err != nil {
+		return result, err
+	}
+	if cfg.ReturnGeneratedKey {
+		result.PrivateKey = key
+	}
+	return result, nil
+}
+
+// wipeBigInt overwrites the words backing x before zeroing it, so the secret
+// doesn't linger in memory that a later allocation might not overwrite.
+func wipeBigInt(x *big.Int) {
+	words := x.Bits()
+	for i := range words {
+		words[i] = 0
+	}
+	x.SetInt64(0)
+}
+
+// wipeBytes zeroes b.
+func wipeBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+// ethereumAddress derives the Ethereum address of a secp256k1 public key.
+func ethereumAddress(pub *tsscrypto.ECPoint) string {
+	return ethcrypto.PubkeyToAddress(ecdsa.PublicKey{
+		Curve: pub.Curve(),
+		X:     pub.X(),
+		Y:     pub.Y(),
+	}).Hex()
+}
@@ -0,0 +1,44 @@
+package dealer
+
+import (
+	"testing"
+
+	tsscrypto "github.com/bnb-chain/tss-lib/v2/crypto"
+)
+
+func TestGenerateAndDealECDSA(t *testing.T) {
+	skipIfShort(t)
+	for _, returnKey := range []bool{false, true} {
+		cfg := *testConfig(SchemeECDSA, 1, 3)
+		cfg.ReturnGeneratedKey = returnKey
+		res, err := GenerateAndDealECDSA(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key, err := ReconstructECDSAKey(res.ECDSAShares)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub := tsscrypto.ScalarBaseMult(res.PublicKey.Curve(), key)
+		if !res.PublicKey.Equals(pub) {
+			t.Error("the returned public key isn't the dealt key's")
+		}
+		for _, s := range res.ECDSAShares {
+			if !s.ECDSAPub.Equals(res.PublicKey) {
+				t.Errorf("share %s holds a different public key", s.ShareID)
+			}
+		}
+		if want := ethereumAddress(pub); res.Address != want || len(want) != 42 {
+			t.Errorf("got address %s, want %s", res.Address, want)
+		}
+
+		switch {
+		case !returnKey && res.PrivateKey != nil:
+			t.Error("the generated key was returned without ReturnGeneratedKey")
+		case returnKey && res.PrivateKey == nil:
+			t.Error("the generated key wasn't returned with ReturnGeneratedKey")
+		case returnKey && res.PrivateKey.Cmp(key) != 0:
+			t.Error("the returned key isn't the dealt key")
+		}
+	}
+}
@@ -0,0 +1,521 @@
+package dealer
+
+import (
+	"crypto/elliptic"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	tsscrypto "github.com/bnb-chain/tss-lib/v2/crypto"
+	eckeygen "github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	ecresharing "github.com/bnb-chain/tss-lib/v2/ecdsa/resharing"
+	edkeygen "github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	edresharing "github.com/bnb-chain/tss-lib/v2/eddsa/resharing"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// partyResult is the save data a party reported once it finished the
+// resharing: an eckeygen.LocalPartySaveData or an
+// edkeygen.LocalPartySaveData, depending on the scheme.
+type partyResult struct {
+	pid  *tss.PartyID
+	data interface{}
+}
+
+// share returns the share ID, secret share and group public key held by r's
+// save data.
+func (r partyResult) share() (shareID, xi *big.Int, pub *tsscrypto.ECPoint) {
+	switch data := r.data.(type) {
+	case eckeygen.LocalPartySaveData:
+		return data.ShareID, data.Xi, data.ECDSAPub
+	case edkeygen.LocalPartySaveData:
+		return data.ShareID, data.Xi, data.EDDSAPub
+	}
+	return nil, nil, nil
+}
+
+type msg struct {
+	from *tss.PartyID
+	data tss.Message
+}
+
+// ImportResult is the outcome of dealing a key from the importer to the new
+// signer group. Only the shares for the scheme that was run are populated.
+type ImportResult struct {
+	ECDSAShares []eckeygen.LocalPartySaveData
+	EdDSAShares []edkeygen.LocalPartySaveData
+
+	// Complete is true only for a finished, verified and stored deal. An
+	// import that is abandoned part way, fails verification or can't store
+	// its shares returns a result with Complete false alongside its error,
+	// holding the shares of the signers that did finish, to tell which ones
+	// got stuck. Those shares must never be used to sign: the deal is
+	// incomplete and the group can't be relied on to hold the key.
+	Complete bool
+
+	// ImporterCompleted reports whether the importer delivered its own save
+	// data, i.e. it finished its half of the resharing protocol.
+	ImporterCompleted bool
+
+	// PublicKey is the group public key the shares were dealt for, and
+	// Address its Ethereum address for secp256k1 keys.
+	PublicKey *tsscrypto.ECPoint
+	Address   string
+
+	// Protocol records the tss-lib version and protocol that produced the
+	// shares.
+	Protocol ProtocolInfo
+
+	// PrivateKey is only set by GenerateAndDealECDSA when the caller asked
+	// for the freshly generated key back.
+	PrivateKey *big.Int
+}
+
+// addShare adds r's save data to the shares of its scheme.
+func (res *ImportResult) addShare(r partyResult) {
+	switch data := r.data.(type) {
+	case eckeygen.LocalPartySaveData:
+		res.ECDSAShares = append(res.ECDSAShares, data)
+	case edkeygen.LocalPartySaveData:
+		res.EdDSAShares = append(res.EdDSAShares, data)
+	}
+}
+
+// defaultResharingTimeout bounds how long we wait for the importer and every
+// signer to report completion before giving up on the deal.
+const defaultResharingTimeout = 5 * time.Minute
+
+// importRun is what an ECDSA and an EDDSA import have in common: the
+// validated group and its resharing parameters, the router between the
+// parties, and collecting and verifying what they report. Only creating the
+// parties is left to the scheme.
+type importRun struct {
+	cfg          *ImportConfig
+	curve        elliptic.Curve
+	importer     *tss.PartyID
+	signers      []*tss.PartyID
+	impParams    *tss.ReSharingParameters
+	signerParams []*tss.ReSharingParameters
+
+	// Simple broadcast router: send each outgoing message to all other
+	// parties.
+	rt      *router
+	parties map[string]tss.Party
+
+	// signerEndCh has one slot per signer: every forwarder can hand its
+	// result over without waiting on the collector, however slowly it drains
+	// the channel. The importer only reports on importerEndCh once it has
+	// finished its half of the protocol, so its result is our proof that the
+	// hand-off ran. An importer that stays on as a signer is a single party
+	// in both groups and reports on signerEndCh like any other signer.
+	signerEndCh   chan partyResult
+	importerEndCh chan partyResult
+
+	// verify, when set, is a further check of the shares once they are
+	// known to reconstruct the importer's key.
+	verify func(*ImportResult) error
+}
+
+// newImportRun checks cfg and plaintextKey are fit for an import of scheme
+// and sets up the group: old=1-of-1, new=(t+1)-of-n. It must be closed once
+// the import is over.
+func newImportRun(cfg *ImportConfig, scheme Scheme, plaintextKey *big.Int) (*importRun, error) {
+	if cfg.Scheme != scheme {
+		return nil, fmt.Errorf("%w: config is for scheme %q, not %q", ErrInvalidConfig, cfg.Scheme, scheme)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if err := checkOutputFree(cfg); err != nil {
+		return nil, err
+	}
+	curve, err := cfg.curve()
+	if err != nil {
+		return nil, err
+	}
+	if !InRange(plaintextKey, curve) {
+		return nil, fmt.Errorf("%w: key must be in [1, N-1] for curve %s", ErrInvalidKeyRange, cfg.Curve)
+	}
+
+	// Define parties: importer (old group) + co-signers (new group)
+	signers := cfg.signerPartyIDs()
+	allOld, allNew, importer, err := peerContexts(cfg, cfg.Importer.partyID(), signers)
+	if err != nil {
+		return nil, err
+	}
+	for _, pid := range signers {
+		if pid.KeyInt().Sign() < 0 {
+			return nil, fmt.Errorf("%w: party %s has negative index %s", ErrInvalidConfig, pid.Id, pid.KeyInt())
+		}
+		cfg.logf("PartyID: %s, Index: %s", pid.Moniker, pid.KeyInt().String())
+	}
+
+	run := &importRun{
+		cfg:           cfg,
+		curve:         curve,
+		importer:      importer,
+		signers:       signers,
+		impParams:     buildReSharingParams(cfg, importer, curve, allOld, allNew),
+		signerParams:  make([]*tss.ReSharingParameters, len(signers)),
+		parties:       make(map[string]tss.Party),
+		signerEndCh:   make(chan partyResult, len(signers)),
+		importerEndCh: make(chan partyResult, 1),
+	}
+	for i, pid := range signers {
+		run.signerParams[i] = buildReSharingParams(cfg, pid, curve, allOld, allNew)
+	}
+	if err := checkReSharingParams(append([]*tss.ReSharingParameters{run.impParams}, run.signerParams...)); err != nil {
+		return nil, err
+	}
+	run.rt = newRouter(run.parties, cfg)
+	return run, nil
+}
+
+// Close stops the run's router and the goroutines feeding it.
+func (run *importRun) Close() error {
+	return run.rt.Close()
+}
+
+// addParty registers party, created with run.rt.outCh(pid) as its outgoing
+// channel, to be routed to and started.
+func (run *importRun) addParty(pid *tss.PartyID, party tss.Party) {
+	run.parties[pid.Id] = party
+}
+
+// collect starts every party and routes their messages until the importer
+// and every signer have reported, a party fails, the deal times out or
+// cancel is closed. onResult, when set, is called with each signer's result
+// as soon as that signer finishes. It returns the signers' results in share
+// ID order and whether the importer completed, including on failure.
+func (run *importRun) collect(onResult func(partyResult), cancel <-chan struct{}) ([]partyResult, bool, error) {
+	cfg := run.cfg
+	var wg sync.WaitGroup
+	// One slot per party, so a failing party never waits on the collector.
+	errCh := make(chan error, len(run.parties))
+	for _, party := range run.parties {
+		wg.Add(1)
+		go func(party tss.Party) {
+			defer wg.Done()
+			if err := party.Start(); err != nil {
+				if pid := party.PartyID(); pid.Id != run.importer.Id || cfg.importerIsSigner() {
+					errCh <- fmt.Errorf("signer %s resharing party failed: %v", pid.Id, err)
+				} else {
+					errCh <- fmt.Errorf("importer resharing party failed: %v", err)
+				}
+			}
+		}(party)
+	}
+	run.rt.start()
+
+	// Collect each signer’s new save data (their individual share + proofs)
+	// along with the importer's own result
+	results := map[string]partyResult{}
+	importerCompleted := false
+	timeout := time.After(cfg.timeout())
+	for len(results) < len(run.signers) || !importerCompleted {
+		select {
+		case r := <-run.signerEndCh:
+			results[r.pid.Id] = r
+			cfg.reportDone(r.pid.Id)
+			if onResult != nil {
+				onResult(r)
+			}
+			if r.pid.Id == run.importer.Id {
+				importerCompleted = true
+			}
+		case <-run.importerEndCh:
+			importerCompleted = true
+			cfg.reportDone(run.importer.Id)
+		case err := <-errCh:
+			return sortResults(results), importerCompleted, err
+		case <-cancel:
+			return sortResults(results), importerCompleted, ErrClosed
+		case <-timeout:
+			if !importerCompleted {
+				return sortResults(results), importerCompleted, fmt.Errorf("%w: importer did not complete within %s", ErrResharingTimeout, cfg.timeout())
+			}
+			return sortResults(results), importerCompleted, fmt.Errorf("%w: only %d of %d signers completed within %s",
+				ErrResharingTimeout, len(results), len(run.signers), cfg.timeout())
+		}
+	}
+	wg.Wait()
+	return sortResults(results), importerCompleted, nil
+}
+
+// finish reconstructs the key from the signers' new shares to make sure it
+// is expectedKey, the importer's key with public key pub, and stores the
+// shares. Every point must live on the one curve instance used throughout
+// the import, never a second instance of the same curve.
+func (run *importRun) finish(results []partyResult, importerCompleted bool, pub *tsscrypto.ECPoint, expectedKey *big.Int) (*ImportResult, error) {
+	cfg := run.cfg
+	importResult := &ImportResult{
+		ImporterCompleted: importerCompleted,
+		PublicKey:         pub,
+		Complete:          true,
+		Protocol:          protocolInfo(cfg),
+	}
+	if cfg.Scheme == SchemeECDSA && cfg.Curve == CurveSecp256k1 {
+		importResult.Address = ethereumAddress(pub)
+	}
+	shares := make([]partyShare, 0, len(results))
+	xs := make([]*big.Int, 0, len(results))
+	ys := make([]*big.Int, 0, len(results))
+	for _, r := range results {
+		shareID, xi, sharePub := r.share()
+		if sharePub == nil || sharePub.Curve() != run.curve {
+			return partialResult(results, importerCompleted), fmt.Errorf("%w: share for %s is not on the import's curve", ErrShareCorrupted, r.pid.Id)
+		}
+		importResult.addShare(r)
+		shares = append(shares, partyShare{id: r.pid.Id, shareID: r.pid.KeyInt(), data: r.data})
+		xs = append(xs, shareID)
+		ys = append(ys, xi)
+		cfg.logf(">>> %s completed with share ID %s", r.pid.Id, shareID)
+	}
+	key, err := reconstructKey(xs, ys, pub, run.curve)
+	if err != nil {
+		return partialResult(results, importerCompleted), err
+	}
+	defer wipeBigInt(key)
+	// Verify it matches the importer's original key
+	if key.Cmp(expectedKey) != 0 {
+		return partialResult(results, importerCompleted), fmt.Errorf("%w: reconstructed key does not match the importer's key", ErrShareCorrupted)
+	}
+	if run.verify != nil {
+		if err := run.verify(importResult); err != nil {
+			return partialResult(results, importerCompleted), err
+		}
+	}
+	cfg.logf(">>> All signers completed successfully. Reconstructed key matches.")
+
+	if err := writeShares(cfg, shares, newManifest(cfg, importResult)); err != nil {
+		return partialResult(results, importerCompleted), err
+	}
+	return importResult, nil
+}
+
+// ImportECDSAKey deals plaintextKey to the signer group described by cfg by
+// resharing it from a 1-of-1 importer group.
+//
+// What happens to plaintextKey depends on the importer's role. An importer
+// that only hands the key over has plaintextKey zeroed by tss-lib once it is
+// dealt, so pass a copy to keep using the key afterwards. An importer that
+// stays on as a signer keeps its secret through the resharing, so tss-lib
+// leaves plaintextKey untouched; wipe it once the import returns.
+func ImportECDSAKey(cfg *ImportConfig, plaintextKey *big.Int) (*ImportResult, error) {
+	return importECDSAKey(cfg, plaintextKey, nil, nil)
+}
+
+// importECDSAKey is ImportECDSAKey, calling emit, when set, with each
+// signer's save data as soon as that signer finishes, and giving up with
+// ErrClosed once cancel is closed.
+func importECDSAKey(cfg *ImportConfig, plaintextKey *big.Int, emit func(SignerResult), cancel <-chan struct{}) (*ImportResult, error) {
+	run, err := newImportRun(cfg, SchemeECDSA, plaintextKey)
+	if err != nil {
+		return nil, err
+	}
+	defer run.Close()
+
+	// Load or generate Paillier & ZK pre-params for each party
+	cfg.logf("Computing local PreParams")
+	preImp, err := cfg.preParams(run.importer.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate pre-params for importer: %w", err)
+	}
+	preSigners := make([]*eckeygen.LocalPreParams, len(run.signers))
+	for i, pid := range run.signers {
+		if pid.Id == run.importer.Id {
+			preSigners[i] = preImp
+			continue
+		}
+		cfg.logf("Computing local PreParams for signer %d", i)
+		preSigners[i], err = cfg.preParams(pid.Id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate pre-params for signer %d: %w", i, err)
+		}
+	}
+	cfg.logf("Finished computing local PreParams")
+
+	// Importer’s save data with the full private key. The importer's party
+	// wipes the key it is handed once dealt, so keep a copy to verify against.
+	expectedKey := new(big.Int).Set(plaintextKey)
+	defer wipeBigInt(expectedKey)
+	save, err := BuildImporterSaveData(SchemeECDSA, plaintextKey, preImp, run.importer, run.curve)
+	if err != nil {
+		return nil, err
+	}
+	impSave := save.(eckeygen.LocalPartySaveData)
+
+	// Create all parties
+	rt := run.rt
+	if !cfg.importerIsSigner() {
+		run.addParty(run.importer, ecresharing.NewLocalParty(
+			run.impParams,
+			impSave,
+			rt.outCh(run.importer),
+			makeEcEndCh(run.importer, run.importerEndCh, rt.done),
+		))
+	}
+	for i, pid := range run.signers {
+		signerSave := impSave
+		if pid.Id != run.importer.Id {
+			// Co-signers start with only their pre-params and the
+			// importer's public data.
+			signerSave = eckeygen.NewLocalPartySaveData(1)
+			signerSave.LocalPreParams = *preSigners[i]
+
+			signerSave.Ks[0] = run.importer.KeyInt()
+			signerSave.BigXj[0] = impSave.BigXj[0]
+			signerSave.NTildej[0] = preImp.NTildei
+			signerSave.H1j[0] = preImp.H1i
+			signerSave.H2j[0] = preImp.H2i
+			signerSave.PaillierPKs[0] = &preImp.PaillierSK.PublicKey
+		}
+		run.addParty(pid, ecresharing.NewLocalParty(
+			run.signerParams[i],
+			signerSave,
+			rt.outCh(pid),
+			makeEcEndCh(pid, run.signerEndCh, rt.done),
+		))
+	}
+
+	var onResult func(partyResult)
+	if emit != nil {
+		onResult = func(r partyResult) {
+			emit(SignerResult{PartyID: r.pid.Id, Data: r.data.(eckeygen.LocalPartySaveData)})
+		}
+	}
+	results, importerCompleted, err := run.collect(onResult, cancel)
+	if err != nil {
+		return partialResult(results, importerCompleted), err
+	}
+	return run.finish(results, importerCompleted, impSave.ECDSAPub, expectedKey)
+}
+
+// ImportEdDSAKey deals plaintextKey to the signer group described by cfg by
+// resharing it from a 1-of-1 importer group.
+//
+// What happens to plaintextKey depends on the importer's role. An importer
+// that only hands the key over has plaintextKey zeroed by tss-lib once it is
+// dealt, so pass a copy to keep using the key afterwards. An importer that
+// stays on as a signer keeps its secret through the resharing, so tss-lib
+// leaves plaintextKey untouched; wipe it once the import returns.
+func ImportEdDSAKey(cfg *ImportConfig, plaintextKey *big.Int) (*ImportResult, error) {
+	return importEdDSAKey(cfg, plaintextKey, nil, nil)
+}
+
+// importEdDSAKey is ImportEdDSAKey, also verifying the shares against seed
+// when the key is an Ed25519 seed's, and giving up with ErrClosed once
+// cancel is closed.
+func importEdDSAKey(cfg *ImportConfig, plaintextKey *big.Int, seed []byte, cancel <-chan struct{}) (*ImportResult, error) {
+	run, err := newImportRun(cfg, SchemeEDDSA, plaintextKey)
+	if err != nil {
+		return nil, err
+	}
+	defer run.Close()
+	if seed != nil {
+		run.verify = func(res *ImportResult) error {
+			return VerifyEdDSAReconstruction(res.EdDSAShares, seed)
+		}
+	}
+
+	// Importer’s save data with the full private key. The importer's party
+	// wipes the key it is handed once dealt, so keep a copy to verify against.
+	expectedKey := new(big.Int).Set(plaintextKey)
+	defer wipeBigInt(expectedKey)
+	save, err := BuildImporterSaveData(SchemeEDDSA, plaintextKey, nil, run.importer, run.curve)
+	if err != nil {
+		return nil, err
+	}
+	impSave := save.(edkeygen.LocalPartySaveData)
+
+	// Create all parties
+	rt := run.rt
+	if !cfg.importerIsSigner() {
+		run.addParty(run.importer, edresharing.NewLocalParty(
+			run.impParams,
+			impSave,
+			rt.outCh(run.importer),
+			makeEdEndCh(run.importer, run.importerEndCh, rt.done),
+		))
+	}
+	for i, pid := range run.signers {
+		signerSave := impSave
+		if pid.Id != run.importer.Id {
+			signerSave = edkeygen.NewLocalPartySaveData(1)
+			signerSave.Ks[0] = run.importer.KeyInt()
+			signerSave.BigXj[0] = impSave.BigXj[0]
+		}
+		run.addParty(pid, edresharing.NewLocalParty(
+			run.signerParams[i],
+			signerSave,
+			rt.outCh(pid),
+			makeEdEndCh(pid, run.signerEndCh, rt.done),
+		))
+	}
+
+	results, importerCompleted, err := run.collect(nil, cancel)
+	if err != nil {
+		return partialResult(results, importerCompleted), err
+	}
+	return run.finish(results, importerCompleted, impSave.EDDSAPub, expectedKey)
+}
+
+// Helpers to wrap channels with party IDs
+func makeEcEndCh(pid *tss.PartyID, endCh chan partyResult, done <-chan struct{}) chan *eckeygen.LocalPartySaveData {
+	// A party reports exactly once, so the forwarder exits after the first
+	// result instead of lingering on a channel that is never closed, or when
+	// done is closed on a party that never finished. endCh must have room for
+	// every party forwarding to it.
+	ch := make(chan *eckeygen.LocalPartySaveData, 1)
+	go func() {
+		select {
+		case sd, ok := <-ch:
+			if ok {
+				endCh <- partyResult{pid: pid, data: *sd}
+			}
+		case <-done:
+		}
+	}()
+	return ch
+}
+
+func makeEdEndCh(pid *tss.PartyID, endCh chan partyResult, done <-chan struct{}) chan *edkeygen.LocalPartySaveData {
+	// As makeEcEndCh.
+	ch := make(chan *edkeygen.LocalPartySaveData, 1)
+	go func() {
+		select {
+		case sd, ok := <-ch:
+			if ok {
+				endCh <- partyResult{pid: pid, data: *sd}
+			}
+		case <-done:
+		}
+	}()
+	return ch
+}
+
+// sortResults orders the collected results by share ID, so shares are
+// reported and written in the same order on every run rather than in
+// completion order.
+func sortResults(results map[string]partyResult) []partyResult {
+	sorted := make([]partyResult, 0, len(results))
+	for _, r := range results {
+		sorted = append(sorted, r)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].pid.KeyInt().Cmp(sorted[j].pid.KeyInt()) < 0
+	})
+	return sorted
+}
+
+// partialResult reports what an abandoned import got done: the signers that
+// finished and whether the importer did. It is never Complete.
+func partialResult(results []partyResult, importerCompleted bool) *ImportResult {
+	res := &ImportResult{ImporterCompleted: importerCompleted}
+	for _, r := range results {
+		res.addShare(r)
+	}
+	return res
+}
@@ -0,0 +1,243 @@
+package dealer
+
+import (
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	edkeygen "github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestImportECDSAKeyVerifiesAgainstTheKey(t *testing.T) {
+	skipIfShort(t)
+	cfg := testConfig(SchemeECDSA, 1, 3)
+	key := testKey(t, cfg)
+	want := new(big.Int).Set(key)
+	res, err := ImportECDSAKey(cfg, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Complete {
+		t.Fatal("import not marked complete")
+	}
+	got, err := ReconstructECDSAKey(res.ECDSAShares)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Cmp(want) != 0 {
+		t.Fatal("shares reconstruct a different key")
+	}
+	if key.Sign() != 0 {
+		t.Error("the caller's key was not zeroed")
+	}
+}
+
+func TestImportEdDSAKeyVerifiesAgainstTheKey(t *testing.T) {
+	skipIfShort(t)
+	cfg := testConfig(SchemeEDDSA, 1, 3)
+	key := testKey(t, cfg)
+	want := new(big.Int).Set(key)
+	res, err := ImportEdDSAKey(cfg, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReconstructEdDSAKey(res.EdDSAShares)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Cmp(want) != 0 {
+		t.Fatal("shares reconstruct a different key")
+	}
+}
+
+func TestImportReturnsSharesWhenWriteFails(t *testing.T) {
+	skipIfShort(t)
+	cfg := testConfig(SchemeEDDSA, 1, 3)
+	// A regular file where the output directory should be. Force skips the
+	// up-front check of the directory, so the deal runs and writing fails.
+	cfg.OutputDir = filepath.Join(t.TempDir(), "not-a-dir")
+	cfg.Force = true
+	if err := os.WriteFile(cfg.OutputDir, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	res, err := ImportEdDSAKey(cfg, testKey(t, cfg))
+	if err == nil {
+		t.Fatal("expected the import to fail")
+	}
+	if res == nil || res.Complete {
+		t.Fatalf("got result %+v, want an incomplete one", res)
+	}
+	if len(res.EdDSAShares) != cfg.PartyCount || !res.ImporterCompleted {
+		t.Fatalf("got %d shares (importer done: %v), want all %d", len(res.EdDSAShares), res.ImporterCompleted, cfg.PartyCount)
+	}
+}
+
+func TestImportWithImporterStayingOn(t *testing.T) {
+	skipIfShort(t)
+	// 1-of-1 to 2-of-3: signer1 holds the key and is one of the three
+	// signers afterwards.
+	cfg := testConfig(SchemeECDSA, 1, 3)
+	cfg.Importer = cfg.Parties[0]
+	key := testKey(t, cfg)
+	want := new(big.Int).Set(key)
+	res, err := ImportECDSAKey(cfg, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Complete || !res.ImporterCompleted {
+		t.Fatalf("complete %v, importer done %v", res.Complete, res.ImporterCompleted)
+	}
+	if len(res.ECDSAShares) != cfg.PartyCount {
+		t.Fatalf("got %d shares, want %d", len(res.ECDSAShares), cfg.PartyCount)
+	}
+	if err := VerifyAllQuorums(res.ECDSAShares, cfg.Threshold, want); err != nil {
+		t.Fatal(err)
+	}
+	// Only an importer that leaves the group has its key zeroed.
+	if key.Cmp(want) != 0 {
+		t.Error("the key of an importer staying on was changed")
+	}
+	for i, s := range res.ECDSAShares {
+		if s.ShareID.Int64() != cfg.Parties[i].Index {
+			t.Errorf("share %d has ID %v, want %d", i, s.ShareID, cfg.Parties[i].Index)
+		}
+	}
+}
+
+func TestValidateImporterStayingOn(t *testing.T) {
+	cfg := testConfig(SchemeECDSA, 1, 3)
+	cfg.Importer = cfg.Parties[0]
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Importer = cfg.Parties[1]
+	if err := cfg.Validate(); !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("importer without the lowest index: got %v, want ErrInvalidConfig", err)
+	}
+	cfg.Importer = PartyConfig{ID: "signer1", Moniker: "Signer1", Index: 7}
+	if err := cfg.Validate(); !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("importer changing index: got %v, want ErrInvalidConfig", err)
+	}
+}
+
+func TestImportFailsWhenImporterIsSabotaged(t *testing.T) {
+	skipIfShort(t)
+	cfg := testConfig(SchemeEDDSA, 1, 3)
+	cfg.Timeout = 10 * time.Second
+	// The importer hears nothing back from the new group, so it never
+	// finishes its half of the protocol.
+	cfg.Deliver = func(from, to *tss.PartyID) bool { return to.Id != cfg.Importer.ID }
+	res, err := ImportEdDSAKey(cfg, testKey(t, cfg))
+	if !errors.Is(err, ErrResharingTimeout) {
+		t.Fatalf("got %v, want ErrResharingTimeout", err)
+	}
+	if !strings.Contains(err.Error(), "importer") {
+		t.Errorf("%q doesn't blame the importer", err)
+	}
+	if res == nil || res.Complete || res.ImporterCompleted {
+		t.Fatalf("got result %+v, want an incomplete one", res)
+	}
+}
+
+func TestEdDSAImportUsesOneCurve(t *testing.T) {
+	cfg := testConfig(SchemeEDDSA, 1, 3)
+	curve, err := cfg.curve()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if curve != tss.Edwards() {
+		t.Fatal("the config's curve isn't tss-lib's ed25519 instance")
+	}
+	allOld, allNew, pid, err := peerContexts(cfg, cfg.Importer.partyID(), cfg.signerPartyIDs())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buildReSharingParams(cfg, pid, curve, allOld, allNew).EC() != curve {
+		t.Error("resharing parameters use another curve")
+	}
+	save, err := BuildImporterSaveData(SchemeEDDSA, testKey(t, cfg), nil, pid, curve)
+	if err != nil {
+		t.Fatal(err)
+	}
+	impSave := save.(edkeygen.LocalPartySaveData)
+	if impSave.EDDSAPub.Curve() != curve || impSave.BigXj[0].Curve() != curve {
+		t.Error("importer's points are on another curve")
+	}
+
+	if testing.Short() {
+		return
+	}
+	res, err := ImportEdDSAKey(cfg, testKey(t, cfg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.PublicKey.Curve() != curve {
+		t.Error("public key is on another curve")
+	}
+	for _, s := range res.EdDSAShares {
+		if s.EDDSAPub.Curve() != curve {
+			t.Errorf("share %v's public key is on another curve", s.ShareID)
+		}
+		for j, x := range s.BigXj {
+			if x.Curve() != curve {
+				t.Errorf("share %v's BigXj[%d] is on another curve", s.ShareID, j)
+			}
+		}
+	}
+}
+
+func TestSortResultsByShareID(t *testing.T) {
+	cfg := testConfig(SchemeECDSA, 2, 6)
+	for run := 0; run < 20; run++ {
+		// Map iteration order differs from run to run.
+		results := map[string]partyResult{}
+		for _, p := range cfg.Parties {
+			results[p.ID] = partyResult{pid: p.partyID()}
+		}
+		for i, r := range sortResults(results) {
+			if r.pid.Id != cfg.Parties[i].ID {
+				t.Fatalf("run %d: result %d is %s, want %s", run, i, r.pid.Id, cfg.Parties[i].ID)
+			}
+		}
+	}
+}
+
+func TestImportOutputOrderIsReproducible(t *testing.T) {
+	skipIfShort(t)
+	cfg := testConfig(SchemeEDDSA, 1, 4)
+	// List the signers out of share ID order; the output follows the IDs.
+	for i, j := 0, len(cfg.Parties)-1; i < j; i, j = i+1, j-1 {
+		cfg.Parties[i], cfg.Parties[j] = cfg.Parties[j], cfg.Parties[i]
+	}
+	cfg.OutputFormat = OutputStdout
+	want := []string{"signer1", "signer2", "signer3", "signer4", ManifestID}
+	for run := 0; run < 2; run++ {
+		var res *ImportResult
+		lines, err := captureStdout(t, func() error {
+			var err error
+			res, err = ImportEdDSAKey(cfg, testKey(t, cfg))
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(lines) != len(want) {
+			t.Fatalf("run %d: got %d lines, want %d", run, len(lines), len(want))
+		}
+		for i, line := range lines {
+			if id, _, _ := strings.Cut(line, " "); id != want[i] {
+				t.Errorf("run %d: line %d is for %s, want %s", run, i, id, want[i])
+			}
+		}
+		for i, s := range res.EdDSAShares {
+			if s.ShareID.Int64() != int64(i+1) {
+				t.Errorf("run %d: share %d has ID %v, want %d", run, i, s.ShareID, i+1)
+			}
+		}
+	}
+}
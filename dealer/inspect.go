@@ -0,0 +1,107 @@
+package dealer
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	tsscrypto "github.com/bnb-chain/tss-lib/v2/crypto"
+	eckeygen "github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	edkeygen "github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// ShareInfo is the public metadata of a share file: enough to tell which key
+// and group it belongs to. It never carries the secret share.
+type ShareInfo struct {
+	Scheme     Scheme
+	Curve      string
+	ShareID    *big.Int
+	PartyIndex int // position among the group's share IDs
+	PartyCount int
+	PublicKey  *tsscrypto.ECPoint
+	Address    string // Ethereum address, secp256k1 keys only
+}
+
+// InspectShare reads the share stored under id and reports its metadata
+// without loading it into a signer. A KeyStore holds shares as plain JSON,
+// so there is nothing to decrypt; sealed shares come from the stdout output
+// and are read with InspectShareLine.
+func InspectShare(store KeyStore, id string) (*ShareInfo, error) {
+	bz, err := store.GetShare(id)
+	if err != nil {
+		return nil, err
+	}
+	defer wipeBytes(bz)
+	return inspectShareJSON(id, bz)
+}
+
+// InspectShareLine reports the metadata of a share line written by the
+// stdout output, and its party id. A line sealed to an output recipient is
+// opened with priv, which must be nil for a plain line.
+func InspectShareLine(line string, priv *RecipientPrivKey) (string, *ShareInfo, error) {
+	id, bz, err := openShareLine(line, priv)
+	if err != nil {
+		return "", nil, err
+	}
+	defer wipeBytes(bz)
+	info, err := inspectShareJSON(id, bz)
+	if err != nil {
+		return "", nil, err
+	}
+	return id, info, nil
+}
+
+// inspectShareJSON reports the metadata of id's serialized save data.
+func inspectShareJSON(id string, bz []byte) (*ShareInfo, error) {
+	var probe struct {
+		ECDSAPub json.RawMessage
+		EDDSAPub json.RawMessage
+	}
+	if err := json.Unmarshal(bz, &probe); err != nil {
+		return nil, fmt.Errorf("%w: failed to parse share %s: %v", ErrShareCorrupted, id, err)
+	}
+
+	var info *ShareInfo
+	switch {
+	case len(probe.ECDSAPub) > 0 && string(probe.ECDSAPub) != "null":
+		var save eckeygen.LocalPartySaveData
+		if err := json.Unmarshal(bz, &save); err != nil {
+			return nil, fmt.Errorf("%w: failed to parse share %s: %v", ErrShareCorrupted, id, err)
+		}
+		info = &ShareInfo{Scheme: SchemeECDSA, ShareID: save.ShareID, PublicKey: save.ECDSAPub}
+		info.PartyIndex, info.PartyCount = shareIndex(save.ShareID, save.Ks)
+	case len(probe.EDDSAPub) > 0 && string(probe.EDDSAPub) != "null":
+		var save edkeygen.LocalPartySaveData
+		if err := json.Unmarshal(bz, &save); err != nil {
+			return nil, fmt.Errorf("%w: failed to parse share %s: %v", ErrShareCorrupted, id, err)
+		}
+		info = &ShareInfo{Scheme: SchemeEDDSA, ShareID: save.ShareID, PublicKey: save.EDDSAPub}
+		info.PartyIndex, info.PartyCount = shareIndex(save.ShareID, save.Ks)
+	default:
+		return nil, fmt.Errorf("%w: %s has no public key", ErrShareCorrupted, id)
+	}
+
+	if info.ShareID == nil || info.PartyIndex < 0 {
+		return nil, fmt.Errorf("%w: %s is not one of its own group's shares", ErrShareCorrupted, id)
+	}
+	name, ok := tss.GetCurveName(info.PublicKey.Curve())
+	if !ok {
+		return nil, fmt.Errorf("%w: %s uses an unknown curve", ErrShareCorrupted, id)
+	}
+	info.Curve = string(name)
+	if info.Curve == CurveSecp256k1 {
+		info.Address = ethereumAddress(info.PublicKey)
+	}
+	return info, nil
+}
+
+// shareIndex finds shareID among the group's share IDs.
+func shareIndex(shareID *big.Int, ks []*big.Int) (int, int) {
+	for j, k := range ks {
+		if shareID != nil && k != nil && k.Cmp(shareID) == 0 {
+			return j, len(ks)
+		}
+	}
+	return -1, len(ks)
+}
@@ -0,0 +1,94 @@
+package dealer
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	tsscrypto "github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestInspectDealtShares(t *testing.T) {
+	skipIfShort(t)
+	cfg := testConfig(SchemeECDSA, 1, 3)
+	store := NewMemoryKeyStore()
+	cfg.Store = store
+	key := testKey(t, cfg)
+	pub := tsscrypto.ScalarBaseMult(tss.S256(), key)
+	if _, err := ImportECDSAKey(cfg, key); err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range cfg.Parties {
+		info, err := InspectShare(store, p.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Scheme != SchemeECDSA || info.Curve != CurveSecp256k1 {
+			t.Errorf("%s: got %s on %s", p.ID, info.Scheme, info.Curve)
+		}
+		if info.ShareID.Cmp(big.NewInt(p.Index)) != 0 || info.PartyIndex != i || info.PartyCount != len(cfg.Parties) {
+			t.Errorf("%s: got share %s at %d of %d", p.ID, info.ShareID, info.PartyIndex, info.PartyCount)
+		}
+		if !info.PublicKey.Equals(pub) {
+			t.Errorf("%s: reports a different public key", p.ID)
+		}
+		if info.Address != ethereumAddress(pub) {
+			t.Errorf("%s: got address %s, want %s", p.ID, info.Address, ethereumAddress(pub))
+		}
+	}
+}
+
+func TestInspectSealedShareLines(t *testing.T) {
+	skipIfShort(t)
+	pub, priv, err := GenerateRecipientKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, otherPriv, err := GenerateRecipientKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := testConfig(SchemeEDDSA, 1, 3)
+	cfg.OutputFormat, cfg.OutputRecipient = OutputStdout, pub.String()
+	key := testKey(t, cfg)
+	want := tsscrypto.ScalarBaseMult(tss.Edwards(), key)
+	lines, err := captureStdout(t, func() error {
+		_, err := ImportEdDSAKey(cfg, key)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seen := 0
+	for _, line := range lines {
+		if strings.HasPrefix(line, ManifestID+" ") {
+			continue
+		}
+		id, info, err := InspectShareLine(line, &priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Scheme != SchemeEDDSA || info.Curve != CurveEd25519 || info.Address != "" {
+			t.Errorf("%s: got %+v", id, info)
+		}
+		if !info.PublicKey.Equals(want) {
+			t.Errorf("%s: reports a different public key", id)
+		}
+		if got := cfg.Parties[info.PartyIndex]; got.ID != id || info.ShareID.Cmp(big.NewInt(got.Index)) != 0 {
+			t.Errorf("%s: got share %s at index %d", id, info.ShareID, info.PartyIndex)
+		}
+		if _, _, err := InspectShareLine(line, &otherPriv); !errors.Is(err, ErrShareCorrupted) {
+			t.Errorf("%s with the wrong key: got %v, want ErrShareCorrupted", id, err)
+		}
+		if _, _, err := InspectShareLine(line, nil); !errors.Is(err, ErrShareCorrupted) {
+			t.Errorf("%s without a key: got %v, want ErrShareCorrupted", id, err)
+		}
+		seen++
+	}
+	if seen != cfg.PartyCount {
+		t.Fatalf("inspected %d share lines, want %d", seen, cfg.PartyCount)
+	}
+}
@@ -0,0 +1,93 @@
+package dealer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+)
+
+// KeyStore is where serialized shares are kept, keyed by party id. The
+// default FileKeyStore keeps them on local disk; implement it to keep them
+// elsewhere, e.g. in a secrets manager.
+//
+// GetShare must return an error wrapping fs.ErrNotExist for an id it holds
+// no share for.
+type KeyStore interface {
+	PutShare(id string, data []byte) error
+	GetShare(id string) ([]byte, error)
+}
+
+// FileKeyStore keeps each share in <Dir>/<id>.json, readable only by the
+// current user.
+type FileKeyStore struct {
+	Dir string
+}
+
+// PutShare writes the share for id, replacing any share already there.
+func (s FileKeyStore) PutShare(id string, data []byte) error {
+	if err := os.MkdirAll(s.Dir, 0700); err != nil {
+		return err
+	}
+	return writeFileAtomic(shareFile(s.Dir, id), data, 0600)
+}
+
+// GetShare reads the share for id.
+func (s FileKeyStore) GetShare(id string) ([]byte, error) {
+	return os.ReadFile(shareFile(s.Dir, id))
+}
+
+// MemoryKeyStore keeps shares in memory, for tests and for callers that
+// hand shares on without persisting them. It is safe for concurrent use.
+type MemoryKeyStore struct {
+	mu     sync.Mutex
+	shares map[string][]byte
+}
+
+// NewMemoryKeyStore returns an empty in-memory store.
+func NewMemoryKeyStore() *MemoryKeyStore {
+	return &MemoryKeyStore{shares: make(map[string][]byte)}
+}
+
+// PutShare stores a copy of data for id.
+func (s *MemoryKeyStore) PutShare(id string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.shares[id] = append([]byte(nil), data...)
+	return nil
+}
+
+// GetShare returns a copy of the share stored for id.
+func (s *MemoryKeyStore) GetShare(id string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.shares[id]
+	if !ok {
+		return nil, fmt.Errorf("share %s: %w", id, fs.ErrNotExist)
+	}
+	return append([]byte(nil), data...), nil
+}
+
+// SaveShareTo serializes a party's save data into store under id.
+func SaveShareTo(store KeyStore, id string, data interface{}) error {
+	bz, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize share for %s: %v", id, err)
+	}
+	defer wipeBytes(bz)
+	return store.PutShare(id, bz)
+}
+
+// LoadShareFrom reads the save data stored under id into data.
+func LoadShareFrom(store KeyStore, id string, data interface{}) error {
+	bz, err := store.GetShare(id)
+	if err != nil {
+		return err
+	}
+	defer wipeBytes(bz)
+	if err := json.Unmarshal(bz, data); err != nil {
+		return fmt.Errorf("%w: failed to parse share %s: %v", ErrShareCorrupted, id, err)
+	}
+	return nil
+}
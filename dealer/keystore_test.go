@@ -0,0 +1,90 @@
+package dealer
+
+import (
+	"errors"
+	"io/fs"
+	"math/big"
+	"os"
+	"testing"
+
+	eckeygen "github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestMemoryKeyStore(t *testing.T) {
+	store := NewMemoryKeyStore()
+	if _, err := store.GetShare("a"); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("got %v, want fs.ErrNotExist", err)
+	}
+	data := []byte("share")
+	if err := store.PutShare("a", data); err != nil {
+		t.Fatal(err)
+	}
+	data[0] = 'S'
+	got, err := store.GetShare("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "share" {
+		t.Fatalf("got %q, want the data as it was put", got)
+	}
+}
+
+func TestSaveShareUsesFileKeyStore(t *testing.T) {
+	dir := t.TempDir()
+	share := shamirShares(t, tss.S256(), big.NewInt(42), 1, 1, 2)[0]
+	if err := SaveShare(dir, "signer1", share); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(shareFile(dir, "signer1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0600 {
+		t.Errorf("share file mode %v, want 0600", fi.Mode().Perm())
+	}
+	var got eckeygen.LocalPartySaveData
+	if err := LoadShareFrom(FileKeyStore{Dir: dir}, "signer1", &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Xi.Cmp(share.Xi) != 0 {
+		t.Fatal("loaded a different share")
+	}
+}
+
+func TestInspectShareFromStore(t *testing.T) {
+	store := NewMemoryKeyStore()
+	shares := shamirShares(t, tss.S256(), big.NewInt(42), 1, 1, 2, 3)
+	if err := SaveShareTo(store, "signer2", shares[1]); err != nil {
+		t.Fatal(err)
+	}
+	info, err := InspectShare(store, "signer2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Scheme != SchemeECDSA || info.Curve != CurveSecp256k1 || info.PartyIndex != 1 || info.PartyCount != 3 {
+		t.Fatalf("got %+v", info)
+	}
+	if _, err := InspectShare(store, "signer9"); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("got %v, want fs.ErrNotExist", err)
+	}
+}
+
+func TestImportIntoMemoryKeyStore(t *testing.T) {
+	skipIfShort(t)
+	cfg := testConfig(SchemeECDSA, 1, 3)
+	store := NewMemoryKeyStore()
+	cfg.Store = store
+	if _, err := ImportECDSAKey(cfg, testKey(t, cfg)); err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range cfg.Parties {
+		var save eckeygen.LocalPartySaveData
+		if err := LoadShareFrom(store, p.ID, &save); err != nil {
+			t.Fatal(err)
+		}
+		if save.ShareID.Cmp(big.NewInt(int64(i+1))) != 0 {
+			t.Errorf("%s holds share %s", p.ID, save.ShareID)
+		}
+	}
+}
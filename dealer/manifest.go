@@ -0,0 +1,62 @@
+package dealer
+
+import (
+	"errors"
+	"io/fs"
+
+	tsscrypto "github.com/bnb-chain/tss-lib/v2/crypto"
+)
+
+// ManifestID is the id a deal's manifest is kept under alongside its shares:
+// <output dir>/_manifest.json, a "_manifest" line on stdout, or that id in a
+// KeyStore. No party may use it.
+const ManifestID = "_manifest"
+
+// Manifest is the public record of a deal, kept with its shares: which key
+// was dealt to which group, and which tss-lib version and protocol did it.
+// It holds nothing secret.
+type Manifest struct {
+	Protocol  ProtocolInfo       `json:"protocol"`
+	Threshold int                `json:"threshold"`
+	Parties   []PartyConfig      `json:"parties"`
+	PublicKey *tsscrypto.ECPoint `json:"public_key"`
+	Address   string             `json:"address,omitempty"`
+}
+
+// newManifest records the deal cfg ran and res holds.
+func newManifest(cfg *ImportConfig, res *ImportResult) Manifest {
+	return Manifest{
+		Protocol:  res.Protocol,
+		Threshold: cfg.Threshold,
+		Parties:   append([]PartyConfig(nil), cfg.Parties...),
+		PublicKey: res.PublicKey,
+		Address:   res.Address,
+	}
+}
+
+// LoadManifest reads the manifest kept in store with a deal's shares.
+func LoadManifest(store KeyStore) (*Manifest, error) {
+	var m Manifest
+	if err := LoadShareFrom(store, ManifestID, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// replaceManifestParty swaps lostID for newParty in the manifest kept in
+// store, if there is one.
+func replaceManifestParty(store KeyStore, lostID string, newParty PartyConfig) error {
+	m, err := LoadManifest(store)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for i, p := range m.Parties {
+		if p.ID == lostID {
+			m.Parties[i] = newParty
+		}
+	}
+	return SaveShareTo(store, ManifestID, m)
+}
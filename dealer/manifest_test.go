@@ -0,0 +1,66 @@
+package dealer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestImportWritesManifest(t *testing.T) {
+	skipIfShort(t)
+	dir := t.TempDir()
+	cfg := testConfig(SchemeEDDSA, 1, 3)
+	cfg.OutputDir = dir
+	res, err := ImportEdDSAKey(cfg, testKey(t, cfg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := LoadManifest(FileKeyStore{Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Protocol != res.Protocol {
+		t.Errorf("manifest protocol %+v, want %+v", m.Protocol, res.Protocol)
+	}
+	if m.Threshold != cfg.Threshold || !reflect.DeepEqual(m.Parties, cfg.Parties) {
+		t.Errorf("manifest group t=%d %v, want t=%d %v", m.Threshold, m.Parties, cfg.Threshold, cfg.Parties)
+	}
+	if !m.PublicKey.Equals(res.PublicKey) {
+		t.Error("manifest holds a different public key")
+	}
+
+	// The manifest counts as earlier output.
+	cfg.Parties[0].ID = "signer1b"
+	if _, err := ImportEdDSAKey(cfg, testKey(t, cfg)); !errors.Is(err, ErrOutputExists) {
+		t.Fatalf("got %v, want ErrOutputExists", err)
+	}
+}
+
+func TestReplaceManifestParty(t *testing.T) {
+	store := NewMemoryKeyStore()
+	cfg := testConfig(SchemeECDSA, 1, 3)
+	if err := replaceManifestParty(store, "signer2", PartyConfig{ID: "signer4"}); err != nil {
+		t.Fatalf("no manifest: %v", err)
+	}
+	if err := SaveShareTo(store, ManifestID, Manifest{Threshold: 1, Parties: cfg.Parties}); err != nil {
+		t.Fatal(err)
+	}
+	if err := replaceManifestParty(store, "signer2", PartyConfig{ID: "signer4", Index: 2}); err != nil {
+		t.Fatal(err)
+	}
+	m, err := LoadManifest(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Parties[1].ID != "signer4" || m.Parties[1].Index != 2 {
+		t.Fatalf("manifest lists %+v in signer2's place", m.Parties[1])
+	}
+}
+
+func TestValidateReservesManifestID(t *testing.T) {
+	cfg := testConfig(SchemeEDDSA, 1, 3)
+	cfg.Parties[2].ID = ManifestID
+	if err := cfg.Validate(); !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("got %v, want ErrInvalidConfig", err)
+	}
+}
@@ -0,0 +1,111 @@
+package dealer
+
+import (
+	"crypto/elliptic"
+	"fmt"
+
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// buildReSharingParams builds the resharing parameters for pid. The importer
+// and every signer must agree on these, so they are always derived from the
+// same config and peer contexts rather than spelled out at each call site.
+func buildReSharingParams(cfg *ImportConfig, pid *tss.PartyID, curve elliptic.Curve, allOld, allNew *tss.PeerContext) *tss.ReSharingParameters {
+	// The old group is the importer alone holding the full key, i.e. 1-of-1.
+	params := tss.NewReSharingParameters(
+		curve,
+		allOld, allNew,
+		pid,
+		len(allOld.IDs()), 0,
+		len(allNew.IDs()), cfg.Threshold)
+	if cfg.NoProofFac {
+		params.SetNoProofFac()
+	}
+	if cfg.NoProofMod {
+		params.SetNoProofMod()
+	}
+	return params
+}
+
+// peerContexts returns the old and new groups' peer contexts, taken from cfg
+// when set there and otherwise built from importer and signers. tss-lib
+// addresses a party by the index its context gave it, so with contexts from
+// cfg the importer and signers are swapped for the contexts' own party IDs:
+// the returned importer, and signers in place.
+func peerContexts(cfg *ImportConfig, importer *tss.PartyID, signers []*tss.PartyID) (*tss.PeerContext, *tss.PeerContext, *tss.PartyID, error) {
+	olds := []*tss.PartyID{importer}
+	allOld := cfg.OldPeerContext
+	if allOld == nil {
+		allOld = tss.NewPeerContext(tss.SortPartyIDs(olds))
+	} else if err := adoptContextParties(allOld, olds, "old"); err != nil {
+		return nil, nil, nil, err
+	}
+	allNew := cfg.NewPeerContext
+	if allNew == nil {
+		allNew = tss.NewPeerContext(tss.SortPartyIDs(signers))
+	} else if err := adoptContextParties(allNew, signers, "new"); err != nil {
+		return nil, nil, nil, err
+	}
+	return allOld, allNew, olds[0], nil
+}
+
+// adoptContextParties checks ctx holds exactly the parties pids, matched by
+// key and id, and replaces each of pids with the context's party ID.
+func adoptContextParties(ctx *tss.PeerContext, pids []*tss.PartyID, group string) error {
+	ids := ctx.IDs()
+	if len(ids) != len(pids) {
+		return fmt.Errorf("%w: %s peer context has %d parties but %d are configured",
+			ErrInvalidConfig, group, len(ids), len(pids))
+	}
+	for i, pid := range pids {
+		found := ids.FindByKey(pid.KeyInt())
+		if found == nil || found.Id != pid.Id {
+			return fmt.Errorf("%w: %s peer context doesn't hold party %s", ErrInvalidConfig, group, pid.Id)
+		}
+		pids[i] = found
+	}
+	return nil
+}
+
+// checkReSharingParams makes sure every party was given the same view of the
+// resharing: curve, committee sizes, thresholds and committee members. A
+// single party with a different threshold would silently corrupt the
+// sharing, so this runs before any party is started.
+func checkReSharingParams(all []*tss.ReSharingParameters) error {
+	if len(all) == 0 {
+		return nil
+	}
+	ref := all[0]
+	for _, p := range all[1:] {
+		switch {
+		case p.EC() != ref.EC():
+			return fmt.Errorf("%w: party %s uses a different curve than %s", ErrParamsMismatch, p.PartyID().Id, ref.PartyID().Id)
+		case p.OldPartyCount() != ref.OldPartyCount() || p.Threshold() != ref.Threshold():
+			return fmt.Errorf("%w: party %s expects old group %d/%d but %s expects %d/%d",
+				ErrParamsMismatch, p.PartyID().Id, p.Threshold(), p.OldPartyCount(),
+				ref.PartyID().Id, ref.Threshold(), ref.OldPartyCount())
+		case p.NewPartyCount() != ref.NewPartyCount() || p.NewThreshold() != ref.NewThreshold():
+			return fmt.Errorf("%w: party %s expects new group %d/%d but %s expects %d/%d",
+				ErrParamsMismatch, p.PartyID().Id, p.NewThreshold(), p.NewPartyCount(),
+				ref.PartyID().Id, ref.NewThreshold(), ref.NewPartyCount())
+		case !samePartyKeys(p.OldParties().IDs(), ref.OldParties().IDs()):
+			return fmt.Errorf("%w: party %s disagrees with %s on the old group members", ErrParamsMismatch, p.PartyID().Id, ref.PartyID().Id)
+		case !samePartyKeys(p.NewParties().IDs(), ref.NewParties().IDs()):
+			return fmt.Errorf("%w: party %s disagrees with %s on the new group members", ErrParamsMismatch, p.PartyID().Id, ref.PartyID().Id)
+		}
+	}
+	return nil
+}
+
+// samePartyKeys reports whether two sorted party lists hold the same keys.
+func samePartyKeys(a, b tss.SortedPartyIDs) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].KeyInt().Cmp(b[i].KeyInt()) != 0 {
+			return false
+		}
+	}
+	return true
+}
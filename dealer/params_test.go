@@ -0,0 +1,119 @@
+package dealer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestCheckReSharingParams(t *testing.T) {
+	cfg := testConfig(SchemeEDDSA, 1, 3)
+	curve, err := cfg.curve()
+	if err != nil {
+		t.Fatal(err)
+	}
+	importer := cfg.Importer.partyID()
+	signers := cfg.signerPartyIDs()
+	allOld := tss.NewPeerContext(tss.SortPartyIDs([]*tss.PartyID{importer}))
+	allNew := tss.NewPeerContext(tss.SortPartyIDs(signers))
+	// params builds every party's parameters, with odd's from odd instead
+	// when set.
+	params := func(odd func(pid *tss.PartyID) *tss.ReSharingParameters) []*tss.ReSharingParameters {
+		all := []*tss.ReSharingParameters{buildReSharingParams(cfg, importer, curve, allOld, allNew)}
+		for i, pid := range signers {
+			if i == 1 && odd != nil {
+				all = append(all, odd(pid))
+				continue
+			}
+			all = append(all, buildReSharingParams(cfg, pid, curve, allOld, allNew))
+		}
+		return all
+	}
+	moreNew := tss.NewPeerContext(tss.SortPartyIDs(append(cfg.signerPartyIDs(), PartyConfig{ID: "signer4", Index: 4}.partyID())))
+
+	for _, tc := range []struct {
+		name string
+		odd  func(pid *tss.PartyID) *tss.ReSharingParameters
+		want error
+	}{
+		{"matching", nil, nil},
+		{"new threshold", func(pid *tss.PartyID) *tss.ReSharingParameters {
+			return tss.NewReSharingParameters(curve, allOld, allNew, pid, 1, 0, 3, 2)
+		}, ErrParamsMismatch},
+		{"old threshold", func(pid *tss.PartyID) *tss.ReSharingParameters {
+			return tss.NewReSharingParameters(curve, allOld, allNew, pid, 1, 1, 3, 1)
+		}, ErrParamsMismatch},
+		{"new party count", func(pid *tss.PartyID) *tss.ReSharingParameters {
+			return tss.NewReSharingParameters(curve, allOld, moreNew, pid, 1, 0, 4, 1)
+		}, ErrParamsMismatch},
+		{"curve", func(pid *tss.PartyID) *tss.ReSharingParameters {
+			return tss.NewReSharingParameters(tss.S256(), allOld, allNew, pid, 1, 0, 3, 1)
+		}, ErrParamsMismatch},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := checkReSharingParams(params(tc.odd)); !errors.Is(err, tc.want) {
+				t.Fatalf("got %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestPeerContextsFromConfig(t *testing.T) {
+	cfg := testConfig(SchemeEDDSA, 1, 3)
+	// The caller's contexts hold their own PartyIDs for the configured
+	// parties, as they would when shared with the rest of a session.
+	oldCtx := tss.NewPeerContext(tss.SortPartyIDs([]*tss.PartyID{cfg.Importer.partyID()}))
+	newCtx := tss.NewPeerContext(tss.SortPartyIDs(cfg.signerPartyIDs()))
+	cfg.OldPeerContext, cfg.NewPeerContext = oldCtx, newCtx
+
+	signers := cfg.signerPartyIDs()
+	allOld, allNew, importer, err := peerContexts(cfg, cfg.Importer.partyID(), signers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if allOld != oldCtx || allNew != newCtx {
+		t.Fatal("the configured peer contexts weren't used")
+	}
+	if importer != oldCtx.IDs()[0] {
+		t.Error("the importer wasn't swapped for the context's party ID")
+	}
+	for _, pid := range signers {
+		if newCtx.IDs().FindByKey(pid.KeyInt()) != pid {
+			t.Errorf("signer %s wasn't swapped for the context's party ID", pid.Id)
+		}
+	}
+}
+
+func TestPeerContextsRejectMismatchedContext(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		ids  func(cfg *ImportConfig) []*tss.PartyID
+	}{
+		{"missing signer", func(cfg *ImportConfig) []*tss.PartyID {
+			return cfg.signerPartyIDs()[:2]
+		}},
+		{"extra signer", func(cfg *ImportConfig) []*tss.PartyID {
+			return append(cfg.signerPartyIDs(), PartyConfig{ID: "signer4", Index: 4}.partyID())
+		}},
+		{"different key", func(cfg *ImportConfig) []*tss.PartyID {
+			ids := cfg.signerPartyIDs()
+			ids[2] = PartyConfig{ID: "signer3", Index: 7}.partyID()
+			return ids
+		}},
+		{"different id", func(cfg *ImportConfig) []*tss.PartyID {
+			ids := cfg.signerPartyIDs()
+			ids[2] = tss.NewPartyID("someone-else", "someone-else", ids[2].KeyInt())
+			return ids
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := testConfig(SchemeEDDSA, 1, 3)
+			cfg.NewPeerContext = tss.NewPeerContext(tss.SortPartyIDs(tc.ids(cfg)))
+			_, _, _, err := peerContexts(cfg, cfg.Importer.partyID(), cfg.signerPartyIDs())
+			if !errors.Is(err, ErrInvalidConfig) {
+				t.Fatalf("got %v, want %v", err, ErrInvalidConfig)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package dealer
+
+import (
+	"log"
+	"sync"
+
+	eckeygen "github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+)
+
+// PreParamsPool keeps ECDSA pre-params generated ahead of time, so a service
+// dealing many keys doesn't pay for the safe-prime search on every import.
+// A background refiller tops the pool up as imports draw from it.
+//
+// Every pre-params handed out by Get belongs to a single party from then on;
+// never Put back pre-params a share has been dealt with.
+type PreParamsPool struct {
+	ready chan *eckeygen.LocalPreParams
+	quit  chan struct{}
+	once  sync.Once
+	wg    sync.WaitGroup
+}
+
+// NewPreParamsPool starts a pool that keeps up to size pre-params ready.
+// Close it to stop the refiller.
+func NewPreParamsPool(size int) *PreParamsPool {
+	return newPreParamsPool(size, true)
+}
+
+// newPreParamsPool is NewPreParamsPool with the refiller optional; without
+// it the pool only holds what is Put.
+func newPreParamsPool(size int, refill bool) *PreParamsPool {
+	if size < 1 {
+		size = 1
+	}
+	p := &PreParamsPool{
+		ready: make(chan *eckeygen.LocalPreParams, size),
+		quit:  make(chan struct{}),
+	}
+	if refill {
+		p.wg.Add(1)
+		go p.refill()
+	}
+	return p
+}
+
+// refill generates pre-params until the pool is closed, blocking while the
+// pool is full.
+func (p *PreParamsPool) refill() {
+	defer p.wg.Done()
+	for {
+		pre, err := generatePreParams()
+		if err != nil {
+			log.Printf("Pre-params pool: %v, retrying", err)
+		} else {
+			select {
+			case p.ready <- pre:
+			case <-p.quit:
+				return
+			}
+		}
+		select {
+		case <-p.quit:
+			return
+		default:
+		}
+	}
+}
+
+// Get takes a set of pre-params from the pool, waiting for the refiller when
+// none are ready. It returns nil once the pool is closed.
+func (p *PreParamsPool) Get() *eckeygen.LocalPreParams {
+	select {
+	case <-p.quit:
+		return nil
+	default:
+	}
+	select {
+	case pre := <-p.ready:
+		return pre
+	case <-p.quit:
+		return nil
+	}
+}
+
+// Put returns pre-params that were taken but never used. They are dropped
+// when the pool is already full.
+func (p *PreParamsPool) Put(pre *eckeygen.LocalPreParams) {
+	if pre == nil {
+		return
+	}
+	select {
+	case p.ready <- pre:
+	default:
+	}
+}
+
+// Close stops the refiller and waits for it to exit. Pre-params still in the
+// pool are discarded. Calling Close more than once is safe.
+func (p *PreParamsPool) Close() error {
+	p.once.Do(func() {
+		close(p.quit)
+	})
+	p.wg.Wait()
+	return nil
+}
@@ -0,0 +1,80 @@
+package dealer
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	eckeygen "github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+)
+
+func TestPreParamsPoolGetPut(t *testing.T) {
+	p := newPreParamsPool(2, false)
+	a, b, c := new(eckeygen.LocalPreParams), new(eckeygen.LocalPreParams), new(eckeygen.LocalPreParams)
+	p.Put(a)
+	p.Put(b)
+	p.Put(c) // dropped: the pool is full
+	p.Put(nil)
+	for _, want := range []*eckeygen.LocalPreParams{a, b} {
+		if got := p.Get(); got != want {
+			t.Fatalf("got %p, want %p", got, want)
+		}
+	}
+	got := make(chan *eckeygen.LocalPreParams)
+	go func() { got <- p.Get() }()
+	select {
+	case <-got:
+		t.Fatal("Get returned from an empty pool")
+	case <-time.After(50 * time.Millisecond):
+	}
+	p.Close()
+	if pre := <-got; pre != nil {
+		t.Fatal("Get returned pre-params after Close")
+	}
+}
+
+func TestConcurrentImportsSharePool(t *testing.T) {
+	skipIfShort(t)
+	const imports = 3
+	cfgs := make([]*ImportConfig, imports)
+	for i := range cfgs {
+		cfgs[i] = testConfig(SchemeECDSA, 1, 3)
+		cfgs[i].PreParamsDir = ""
+	}
+	perImport := cfgs[0].PartyCount + 1
+
+	// No refiller: the imports can only use what was generated up front,
+	// so none of them runs its own safe-prime search.
+	pool := newPreParamsPool(imports*perImport, false)
+	defer pool.Close()
+	for i := 0; i < imports*perImport; i++ {
+		pre, err := loadOrGeneratePreParams(testPreParamsDir, fmt.Sprintf("pool%d", i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		pool.Put(pre)
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, imports)
+	start := time.Now()
+	for i, cfg := range cfgs {
+		cfg.PreParamsPool = pool
+		wg.Add(1)
+		go func(i int, cfg *ImportConfig) {
+			defer wg.Done()
+			_, errs[i] = ImportECDSAKey(cfg, testKey(t, cfg))
+		}(i, cfg)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("import %d: %v", i, err)
+		}
+	}
+	if left := len(pool.ready); left != 0 {
+		t.Fatalf("%d pre-params left in the pool, want every one used", left)
+	}
+	t.Logf("%d imports from the pool took %s", imports, time.Since(start))
+}
@@ -0,0 +1,110 @@
+package dealer
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	eckeygen "github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+)
+
+// preParamsTimeout bounds the safe-prime search for a single party.
+const preParamsTimeout = 1 * time.Minute
+
+// Minimum pre-params sizes, matching what tss-lib v2 generates: a 2048-bit
+// Paillier modulus and an NTilde built from two 1024-bit safe primes.
+const (
+	minPaillierModulusBits = 2048
+	minSafePrimeBits       = 1024
+)
+
+// CheckPreParamsSecurity checks pre-params against the library's current
+// minimums: the Paillier modulus and NTilde must be large enough, and NTilde
+// must be the product of the safe primes 2P+1 and 2Q+1. Pre-params that fail
+// should be regenerated.
+func CheckPreParamsSecurity(p *eckeygen.LocalPreParams) error {
+	if p == nil || p.PaillierSK == nil || p.PaillierSK.N == nil || p.NTildei == nil || p.P == nil || p.Q == nil {
+		return fmt.Errorf("%w: pre-params are incomplete; regenerate them", ErrWeakPreParams)
+	}
+	if bits := p.PaillierSK.N.BitLen(); bits < minPaillierModulusBits {
+		return fmt.Errorf("%w: Paillier modulus is %d bits, need %d; regenerate them",
+			ErrWeakPreParams, bits, minPaillierModulusBits)
+	}
+	if bits := p.NTildei.BitLen(); bits < 2*minSafePrimeBits {
+		return fmt.Errorf("%w: NTilde is %d bits, need %d; regenerate them",
+			ErrWeakPreParams, bits, 2*minSafePrimeBits)
+	}
+	nTilde := big.NewInt(1)
+	for _, sg := range []*big.Int{p.P, p.Q} {
+		safe := new(big.Int).Lsh(sg, 1)
+		safe.Add(safe, big.NewInt(1))
+		if safe.BitLen() < minSafePrimeBits {
+			return fmt.Errorf("%w: safe prime is %d bits, need %d; regenerate them",
+				ErrWeakPreParams, safe.BitLen(), minSafePrimeBits)
+		}
+		if !sg.ProbablyPrime(30) || !safe.ProbablyPrime(30) {
+			return fmt.Errorf("%w: NTilde factor is not a safe prime; regenerate them", ErrWeakPreParams)
+		}
+		nTilde.Mul(nTilde, safe)
+	}
+	if nTilde.Cmp(p.NTildei) != 0 {
+		return fmt.Errorf("%w: NTilde is not the product of its safe primes; regenerate them", ErrWeakPreParams)
+	}
+	return nil
+}
+
+// loadOrGeneratePreParams returns the pre-params cached for partyID in dir,
+// generating and caching them first if there are none yet. An empty dir
+// disables the cache.
+func loadOrGeneratePreParams(dir, partyID string) (*eckeygen.LocalPreParams, error) {
+	if dir == "" {
+		return generatePreParams()
+	}
+
+	path := filepath.Join(dir, partyID+".json")
+	bz, err := os.ReadFile(path)
+	if err == nil {
+		pre := new(eckeygen.LocalPreParams)
+		if err := json.Unmarshal(bz, pre); err != nil {
+			return nil, fmt.Errorf("failed to parse pre-params %s: %v", path, err)
+		}
+		if !pre.ValidateWithProof() {
+			return nil, fmt.Errorf("cached pre-params %s are invalid", path)
+		}
+		if err := CheckPreParamsSecurity(pre); err != nil {
+			return nil, fmt.Errorf("cached pre-params %s: %w", path, err)
+		}
+		return pre, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	pre, err := generatePreParams()
+	if err != nil {
+		return nil, err
+	}
+	if bz, err = json.Marshal(pre); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	if err := writeFileAtomic(path, bz, 0600); err != nil {
+		return nil, err
+	}
+	return pre, nil
+}
+
+// generatePreParams runs the safe-prime search, which only fails when it
+// can't finish within preParamsTimeout.
+func generatePreParams() (*eckeygen.LocalPreParams, error) {
+	pre, err := eckeygen.GeneratePreParams(preParamsTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPreParamsTimeout, err)
+	}
+	return pre, nil
+}
@@ -0,0 +1,53 @@
+package dealer
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	eckeygen "github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+)
+
+func TestCheckPreParamsSecurity(t *testing.T) {
+	skipIfShort(t)
+	pre, err := loadOrGeneratePreParams(testPreParamsDir, "security")
+	if err != nil {
+		t.Fatal(err)
+	}
+	plusOne := func(x *big.Int) *big.Int { return new(big.Int).Add(x, big.NewInt(1)) }
+
+	for _, tc := range []struct {
+		name   string
+		modify func(p *eckeygen.LocalPreParams)
+		want   string // in the error; empty when the pre-params should pass
+	}{
+		{"valid", func(p *eckeygen.LocalPreParams) {}, ""},
+		{"incomplete", func(p *eckeygen.LocalPreParams) { p.NTildei = nil }, "incomplete"},
+		{"small Paillier modulus", func(p *eckeygen.LocalPreParams) {
+			sk := *p.PaillierSK
+			sk.N = new(big.Int).Lsh(big.NewInt(1), 1023)
+			p.PaillierSK = &sk
+		}, "Paillier modulus is 1024 bits"},
+		{"P not a safe prime", func(p *eckeygen.LocalPreParams) { p.P = plusOne(p.P) }, "not a safe prime"},
+		{"Q not a safe prime", func(p *eckeygen.LocalPreParams) { p.Q = plusOne(p.Q) }, "not a safe prime"},
+		{"NTilde not their product", func(p *eckeygen.LocalPreParams) {
+			p.NTildei = new(big.Int).Add(p.NTildei, big.NewInt(2))
+		}, "not the product"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := *pre
+			tc.modify(&p)
+			err := CheckPreParamsSecurity(&p)
+			if tc.want == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrWeakPreParams) || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("got %v, want ErrWeakPreParams mentioning %q", err, tc.want)
+			}
+		})
+	}
+}
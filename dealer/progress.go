@@ -0,0 +1,35 @@
+package dealer
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// ExpectedRounds is the number of rounds tss-lib's resharing protocol runs
+// for scheme, or 0 for an unknown scheme. In tss-lib v2.0.2 both the ECDSA
+// and the EDDSA resharing run five rounds, the last of which sends nothing.
+func ExpectedRounds(scheme Scheme) int {
+	switch scheme {
+	case SchemeECDSA, SchemeEDDSA:
+		return 5
+	}
+	return 0
+}
+
+// messageRoundRe pulls the round out of tss-lib message types such as
+// "binance.tsslib.ecdsa.resharing.DGRound2Message1".
+var messageRoundRe = regexp.MustCompile(`Round(\d+)Message`)
+
+// messageRound returns the protocol round a message type belongs to, or 0 if
+// it can't be told.
+func messageRound(msgType string) int {
+	m := messageRoundRe.FindStringSubmatch(msgType)
+	if m == nil {
+		return 0
+	}
+	round, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0
+	}
+	return round
+}
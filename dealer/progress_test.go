@@ -0,0 +1,87 @@
+package dealer
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestExpectedRounds(t *testing.T) {
+	for scheme, want := range map[Scheme]int{SchemeECDSA: 5, SchemeEDDSA: 5, "rsa": 0} {
+		if got := ExpectedRounds(scheme); got != want {
+			t.Errorf("ExpectedRounds(%q) = %d, want %d", scheme, got, want)
+		}
+	}
+}
+
+func TestMessageRound(t *testing.T) {
+	for typ, want := range map[string]int{
+		"binance.tsslib.ecdsa.resharing.DGRound1Message":  1,
+		"binance.tsslib.ecdsa.resharing.DGRound4Message2": 4,
+		"binance.tsslib.eddsa.resharing.DGRound3Message1": 3,
+		"binance.tsslib.ecdsa.signing.SignRoundOne":       0,
+	} {
+		if got := messageRound(typ); got != want {
+			t.Errorf("messageRound(%q) = %d, want %d", typ, got, want)
+		}
+	}
+}
+
+// checkAllRounds runs an import reporting progress and makes sure every
+// party advanced through every round.
+func checkAllRounds(t *testing.T, cfg *ImportConfig, run func() error) {
+	t.Helper()
+	var mu sync.Mutex
+	last := map[string]int{}
+	cfg.Progress = func(partyID string, round, total int) {
+		mu.Lock()
+		defer mu.Unlock()
+		if total != ExpectedRounds(cfg.Scheme) {
+			t.Errorf("%s: total %d, want %d", partyID, total, ExpectedRounds(cfg.Scheme))
+		}
+		if round < 1 || round > total {
+			t.Errorf("%s: round %d out of range", partyID, round)
+		}
+		// The router and the collector report on their own goroutines, so
+		// only the furthest round reached is meaningful.
+		if round > last[partyID] {
+			last[partyID] = round
+		}
+	}
+	if err := run(); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	want := ExpectedRounds(cfg.Scheme)
+	for _, id := range append([]string{cfg.Importer.ID}, partyIDs(cfg)...) {
+		if last[id] != want {
+			t.Errorf("%s stopped at round %d of %d", id, last[id], want)
+		}
+	}
+}
+
+func partyIDs(cfg *ImportConfig) []string {
+	ids := make([]string, len(cfg.Parties))
+	for i, p := range cfg.Parties {
+		ids[i] = p.ID
+	}
+	return ids
+}
+
+func TestEdDSAProgressReachesEveryRound(t *testing.T) {
+	skipIfShort(t)
+	cfg := testConfig(SchemeEDDSA, 1, 3)
+	checkAllRounds(t, cfg, func() error {
+		_, err := ImportEdDSAKey(cfg, testKey(t, cfg))
+		return err
+	})
+}
+
+func TestECDSAProgressReachesEveryRound(t *testing.T) {
+	skipIfShort(t)
+	cfg := testConfig(SchemeECDSA, 1, 3)
+	checkAllRounds(t, cfg, func() error {
+		_, err := ImportECDSAKey(cfg, testKey(t, cfg))
+		return err
+	})
+}
@@ -0,0 +1,66 @@
+package dealer
+
+import "runtime/debug"
+
+// tssLibPath is the module path of the tss-lib the shares are produced with.
+const tssLibPath = "github.com/bnb-chain/tss-lib/v2"
+
+// ProtocolInfo records which implementation and protocol produced a set of
+// shares, so they are later signed with a compatible tss-lib.
+type ProtocolInfo struct {
+	// LibraryVersion is the tss-lib module version this binary was built
+	// with, or "unknown" when build info isn't available.
+	LibraryVersion string `json:"library_version"`
+	Scheme         Scheme `json:"scheme"`
+	Curve          string `json:"curve"`
+	// Protocol names the threshold signature protocol the shares are for,
+	// and Resharing the tss-lib resharing protocol that dealt them.
+	Protocol  string `json:"protocol"`
+	Resharing string `json:"resharing"`
+	// NoProofFac and NoProofMod record whether the Paillier proofs were
+	// skipped during the deal.
+	NoProofFac bool `json:"no_proof_fac,omitempty"`
+	NoProofMod bool `json:"no_proof_mod,omitempty"`
+}
+
+// protocolInfo describes the deal cfg runs.
+func protocolInfo(cfg *ImportConfig) ProtocolInfo {
+	info := ProtocolInfo{
+		LibraryVersion: tssLibVersion(),
+		Scheme:         cfg.Scheme,
+		Curve:          cfg.Curve,
+	}
+	switch cfg.Scheme {
+	case SchemeECDSA:
+		info.Protocol = "GG18 ECDSA"
+		info.Resharing = "tss-lib ecdsa/resharing"
+		info.NoProofFac = cfg.NoProofFac
+		info.NoProofMod = cfg.NoProofMod
+	case SchemeEDDSA:
+		info.Protocol = "threshold EdDSA"
+		info.Resharing = "tss-lib eddsa/resharing"
+	}
+	return info
+}
+
+// tssLibVersion reads the tss-lib version from the binary's build info,
+// following any replace directive.
+func tssLibVersion() string {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	for _, dep := range bi.Deps {
+		if dep.Path != tssLibPath {
+			continue
+		}
+		if dep.Replace != nil {
+			dep = dep.Replace
+		}
+		if dep.Version == "" {
+			return "unknown"
+		}
+		return dep.Version
+	}
+	return "unknown"
+}
@@ -0,0 +1,113 @@
+package dealer
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand/v2"
+
+	eckeygen "github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+)
+
+// maxQuorumChecks caps how many subsets of each size VerifyAllQuorums
+// reconstructs. C(n, t+1) grows quickly (C(30, 15) is over 150 million), so
+// beyond the cap a random sample of this many subsets is checked instead of
+// all of them.
+const maxQuorumChecks = 10000
+
+// VerifyAllQuorums checks the shares were dealt with threshold t: every
+// combination of t+1 shares reconstructs expected, and no combination of t
+// shares does. The second check catches a deal of the wrong degree, which a
+// quorum check alone lets through. When there are more than maxQuorumChecks
+// subsets of a size, a random sample of that many is checked.
+//
+// The threshold is passed in because a share doesn't record it: the save
+// data holds the group's share IDs, not the degree they were dealt with.
+func VerifyAllQuorums(results []eckeygen.LocalPartySaveData, threshold int, expected *big.Int) error {
+	n := len(results)
+	if threshold < 1 || threshold >= n {
+		return fmt.Errorf("%w: threshold %d is outside [1, %d] for %d shares", ErrInvalidConfig, threshold, n-1, n)
+	}
+	subset := func(idx []int) []eckeygen.LocalPartySaveData {
+		shares := make([]eckeygen.LocalPartySaveData, len(idx))
+		for i, j := range idx {
+			shares[i] = results[j]
+		}
+		return shares
+	}
+
+	err := forEachSubset(n, threshold+1, func(idx []int) error {
+		shares := subset(idx)
+		key, err := ReconstructECDSAKey(shares)
+		if err != nil {
+			return fmt.Errorf("quorum %v: %w", shareIDs(shares), err)
+		}
+		if key.Cmp(expected) != 0 {
+			return fmt.Errorf("%w: quorum %v reconstructs a different key", ErrShareCorrupted, shareIDs(shares))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	// Too few shares must fail the public key check and nothing else; any
+	// other error means the subset couldn't be checked at all.
+	return forEachSubset(n, threshold, func(idx []int) error {
+		shares := subset(idx)
+		_, err := ReconstructECDSAKey(shares)
+		switch {
+		case err == nil:
+			return fmt.Errorf("%w: %d shares %v reconstruct the key, the threshold is lower than %d",
+				ErrShareCorrupted, threshold, shareIDs(shares), threshold)
+		case !errors.Is(err, ErrShareCorrupted):
+			return fmt.Errorf("%d shares %v: %w", threshold, shareIDs(shares), err)
+		}
+		return nil
+	})
+}
+
+// forEachSubset calls check with every k-element subset of [0, n), as
+// sorted indexes, or with a random sample of maxQuorumChecks of them when
+// there are more. It stops at the first error.
+func forEachSubset(n, k int, check func(idx []int) error) error {
+	total := new(big.Int).Binomial(int64(n), int64(k))
+	if total.Cmp(big.NewInt(maxQuorumChecks)) > 0 {
+		for i := 0; i < maxQuorumChecks; i++ {
+			if err := check(rand.Perm(n)[:k]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// Walk every combination in lexicographic order.
+	idx := make([]int, k)
+	for i := range idx {
+		idx[i] = i
+	}
+	for {
+		if err := check(idx); err != nil {
+			return err
+		}
+		i := k - 1
+		for i >= 0 && idx[i] == n-k+i {
+			i--
+		}
+		if i < 0 {
+			return nil
+		}
+		idx[i]++
+		for j := i + 1; j < k; j++ {
+			idx[j] = idx[j-1] + 1
+		}
+	}
+}
+
+// shareIDs lists the share IDs of shares, for error messages.
+func shareIDs(shares []eckeygen.LocalPartySaveData) []*big.Int {
+	ids := make([]*big.Int, len(shares))
+	for i, s := range shares {
+		ids[i] = s.ShareID
+	}
+	return ids
+}
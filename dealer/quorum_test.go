@@ -0,0 +1,55 @@
+package dealer
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestVerifyAllQuorums(t *testing.T) {
+	curve := tss.S256()
+	key := big.NewInt(0xdea1)
+	for _, tc := range []struct {
+		name      string
+		degree    int
+		threshold int
+		want      error
+	}{
+		{"right degree", 2, 2, nil},
+		{"degree too low", 1, 2, ErrShareCorrupted},
+		{"degree too high", 3, 2, ErrShareCorrupted},
+		{"threshold zero", 2, 0, ErrInvalidConfig},
+		{"threshold n", 2, 5, ErrInvalidConfig},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			shares := shamirShares(t, curve, key, tc.degree, 1, 2, 3, 4, 5)
+			err := VerifyAllQuorums(shares, tc.threshold, key)
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("got %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestVerifyAllQuorumsWrongKey(t *testing.T) {
+	shares := shamirShares(t, tss.S256(), big.NewInt(7), 1, 1, 2, 3)
+	if err := VerifyAllQuorums(shares, 1, big.NewInt(8)); !errors.Is(err, ErrShareCorrupted) {
+		t.Fatalf("got %v, want ErrShareCorrupted", err)
+	}
+}
+
+func TestForEachSubset(t *testing.T) {
+	seen := map[[3]int]bool{}
+	err := forEachSubset(5, 3, func(idx []int) error {
+		seen[[3]int{idx[0], idx[1], idx[2]}] = true
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(seen) != 10 {
+		t.Fatalf("visited %d subsets, want C(5, 3) = 10", len(seen))
+	}
+}
@@ -0,0 +1,107 @@
+package dealer
+
+import (
+	"crypto/elliptic"
+	"fmt"
+	"math/big"
+
+	tsscrypto "github.com/bnb-chain/tss-lib/v2/crypto"
+	eckeygen "github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	edkeygen "github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+)
+
+// ReconstructECDSAKey recombines the private key from a quorum of shares and
+// checks it against the shares' public key. It needs at least t+1 shares.
+// Only ever do this for verification: the point of dealing is that the key
+// doesn't exist in one place.
+func ReconstructECDSAKey(shares []eckeygen.LocalPartySaveData) (*big.Int, error) {
+	if len(shares) == 0 {
+		return nil, fmt.Errorf("no shares to reconstruct from")
+	}
+	xs := make([]*big.Int, len(shares))
+	ys := make([]*big.Int, len(shares))
+	for i, s := range shares {
+		xs[i], ys[i] = s.ShareID, s.Xi
+	}
+	pub := shares[0].ECDSAPub
+	if pub == nil {
+		return nil, fmt.Errorf("%w: shares have no public key", ErrShareCorrupted)
+	}
+	for _, s := range shares[1:] {
+		if !sameKs(s.Ks, shares[0].Ks) {
+			return nil, fmt.Errorf("%w: share %s lists the group's share IDs differently from share %s",
+				ErrKsMismatch, s.ShareID, shares[0].ShareID)
+		}
+	}
+	return reconstructKey(xs, ys, pub, pub.Curve())
+}
+
+// ReconstructEdDSAKey is ReconstructECDSAKey for EDDSA shares.
+func ReconstructEdDSAKey(shares []edkeygen.LocalPartySaveData) (*big.Int, error) {
+	if len(shares) == 0 {
+		return nil, fmt.Errorf("no shares to reconstruct from")
+	}
+	xs := make([]*big.Int, len(shares))
+	ys := make([]*big.Int, len(shares))
+	for i, s := range shares {
+		xs[i], ys[i] = s.ShareID, s.Xi
+	}
+	pub := shares[0].EDDSAPub
+	if pub == nil {
+		return nil, fmt.Errorf("%w: shares have no public key", ErrShareCorrupted)
+	}
+	for _, s := range shares[1:] {
+		if !sameKs(s.Ks, shares[0].Ks) {
+			return nil, fmt.Errorf("%w: share %s lists the group's share IDs differently from share %s",
+				ErrKsMismatch, s.ShareID, shares[0].ShareID)
+		}
+	}
+	return reconstructKey(xs, ys, pub, pub.Curve())
+}
+
+// reconstructKey interpolates the shares (xs[i], ys[i]) at zero on curve and
+// checks the result is the private key for pub.
+func reconstructKey(xs, ys []*big.Int, pub *tsscrypto.ECPoint, curve elliptic.Curve) (*big.Int, error) {
+	key, err := interpolateAt(xs, ys, big.NewInt(0), curve)
+	if err != nil {
+		return nil, err
+	}
+	if !tsscrypto.ScalarBaseMult(curve, key).Equals(pub) {
+		return nil, fmt.Errorf("%w: %d shares don't reconstruct the public key (too few for the threshold?)",
+			ErrShareCorrupted, len(xs))
+	}
+	return key, nil
+}
+
+// sameKs reports whether two shares list the same share IDs in the same
+// order.
+func sameKs(a, b []*big.Int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] == nil || b[i] == nil || a[i].Cmp(b[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// interpolateAt evaluates the polynomial through the points (xs[i], ys[i]) at
+// x, modulo the curve order.
+func interpolateAt(xs, ys []*big.Int, x *big.Int, curve elliptic.Curve) (*big.Int, error) {
+	n := curve.Params().N
+	y := big.NewInt(0)
+	for j := range xs {
+		if xs[j] == nil || ys[j] == nil {
+			return nil, fmt.Errorf("%w: share is missing its ID or secret", ErrShareCorrupted)
+		}
+		lambda, err := lagrangeCoefficientAt(j, xs, x, n)
+		if err != nil {
+			return nil, err
+		}
+		y.Add(y, lambda.Mul(lambda, ys[j]))
+		y.Mod(y, n)
+	}
+	return y, nil
+}
@@ -0,0 +1,40 @@
+package dealer
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// permuteKs swaps the first two entries of a copy of ks.
+func permuteKs(ks []*big.Int) []*big.Int {
+	permuted := append([]*big.Int(nil), ks...)
+	permuted[0], permuted[1] = permuted[1], permuted[0]
+	return permuted
+}
+
+func TestReconstructECDSAKeyRejectsPermutedKs(t *testing.T) {
+	key := big.NewInt(0x5eed)
+	shares := shamirShares(t, tss.S256(), key, 1, 1, 2, 3)
+	if got, err := ReconstructECDSAKey(shares); err != nil || got.Cmp(key) != 0 {
+		t.Fatalf("unpermuted shares: got %v, %v", got, err)
+	}
+	shares[1].Ks = permuteKs(shares[1].Ks)
+	if _, err := ReconstructECDSAKey(shares); !errors.Is(err, ErrKsMismatch) {
+		t.Fatalf("got %v, want ErrKsMismatch", err)
+	}
+}
+
+func TestReconstructEdDSAKeyRejectsPermutedKs(t *testing.T) {
+	key := big.NewInt(0x5eed)
+	shares := shamirEdShares(t, key, 1, 1, 2, 3)
+	if got, err := ReconstructEdDSAKey(shares); err != nil || got.Cmp(key) != 0 {
+		t.Fatalf("unpermuted shares: got %v, %v", got, err)
+	}
+	shares[2].Ks = permuteKs(shares[2].Ks)
+	if _, err := ReconstructEdDSAKey(shares); !errors.Is(err, ErrKsMismatch) {
+		t.Fatalf("got %v, want ErrKsMismatch", err)
+	}
+}
@@ -0,0 +1,89 @@
+package dealer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sync"
+
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// MessageRecorder captures every message the router delivers, in delivery
+// order, so a failed resharing can be replayed into fresh party instances
+// with ReplayFrom. Recordings hold the protocol messages in the clear; treat
+// them as sensitive as the shares.
+type MessageRecorder struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// recordedDelivery is one message handed to one party, stored as a line of
+// JSON.
+type recordedDelivery struct {
+	From        recordedParty `json:"from"`
+	To          string        `json:"to"`
+	IsBroadcast bool          `json:"is_broadcast"`
+	Payload     []byte        `json:"payload"`
+}
+
+// recordedParty is enough of a tss.PartyID to rebuild it: the round code
+// addresses a sender by its index.
+type recordedParty struct {
+	ID      string `json:"id"`
+	Moniker string `json:"moniker"`
+	Key     []byte `json:"key"`
+	Index   int    `json:"index"`
+}
+
+// RecordTo returns a recorder writing to w. Set it as ImportConfig.Recorder.
+func RecordTo(w io.Writer) *MessageRecorder {
+	return &MessageRecorder{enc: json.NewEncoder(w)}
+}
+
+// record appends the delivery of payload from from to the party to.
+func (rec *MessageRecorder) record(from, to *tss.PartyID, payload []byte, isBroadcast bool) error {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return rec.enc.Encode(recordedDelivery{
+		From: recordedParty{
+			ID:      from.Id,
+			Moniker: from.Moniker,
+			Key:     from.Key,
+			Index:   from.Index,
+		},
+		To:          to.Id,
+		IsBroadcast: isBroadcast,
+		Payload:     payload,
+	})
+}
+
+// ReplayFrom feeds the deliveries recorded in r to parties, keyed by party
+// id, in recorded order. The parties must be fresh instances built from the
+// same parameters and save data as the recorded run, and already started.
+// Deliveries to parties not in the map are skipped. A truncated or corrupt
+// recording fails at its first bad line, once the deliveries before that
+// line have been made.
+func ReplayFrom(r io.Reader, parties map[string]tss.Party) error {
+	dec := json.NewDecoder(r)
+	for n := 1; ; n++ {
+		var d recordedDelivery
+		if err := dec.Decode(&d); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("failed to read recorded message %d: %v", n, err)
+		}
+		p := parties[d.To]
+		if p == nil {
+			continue
+		}
+		from := tss.NewPartyID(d.From.ID, d.From.Moniker, new(big.Int).SetBytes(d.From.Key))
+		from.Index = d.From.Index
+		if _, err := p.UpdateFromBytes(d.Payload, from, d.IsBroadcast); err != nil {
+			return fmt.Errorf("replaying message %d from %s to %s: %v", n, d.From.ID, d.To, err)
+		}
+	}
+}
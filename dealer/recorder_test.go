@@ -0,0 +1,106 @@
+package dealer
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	edkeygen "github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	edresharing "github.com/bnb-chain/tss-lib/v2/eddsa/resharing"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestReplayRecordedImport(t *testing.T) {
+	skipIfShort(t)
+	cfg := testConfig(SchemeEDDSA, 1, 3)
+	var recording bytes.Buffer
+	cfg.Recorder = RecordTo(&recording)
+	key := testKey(t, cfg)
+	res, err := ImportEdDSAKey(cfg, new(big.Int).Set(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Fresh signers, built as the import built them, get their shares from
+	// the recorded importer's messages alone.
+	cfg.Recorder = nil
+	run, err := newImportRun(cfg, SchemeEDDSA, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer run.Close()
+	endCh := make(chan *edkeygen.LocalPartySaveData, len(run.signers))
+	parties := map[string]tss.Party{}
+	for i, pid := range run.signers {
+		save := edkeygen.NewLocalPartySaveData(1)
+		save.Ks[0] = run.importer.KeyInt()
+		save.BigXj[0] = res.PublicKey
+		// What the fresh parties send is never routed.
+		party := edresharing.NewLocalParty(run.signerParams[i], save, make(chan tss.Message, 100), endCh)
+		if err := party.Start(); err != nil {
+			t.Fatal(err)
+		}
+		parties[pid.Id] = party
+	}
+	if err := ReplayFrom(&recording, parties); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]*big.Int{}
+	for _, s := range res.EdDSAShares {
+		want[s.ShareID.String()] = s.Xi
+	}
+	for range run.signers {
+		select {
+		case s := <-endCh:
+			if xi := want[s.ShareID.String()]; xi == nil || xi.Cmp(s.Xi) != 0 {
+				t.Errorf("replayed share %v differs from the recorded run's", s.ShareID)
+			}
+		case <-time.After(30 * time.Second):
+			t.Fatal("replayed signers didn't finish")
+		}
+	}
+}
+
+// recordedMessages records n broadcasts from a to b in group.
+func recordedMessages(t *testing.T, group map[string]*fakeParty, n int) string {
+	t.Helper()
+	var recording bytes.Buffer
+	rec := RecordTo(&recording)
+	for i := 0; i < n; i++ {
+		if err := rec.record(group["a"].pid, group["b"].pid, []byte{byte(i)}, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return recording.String()
+}
+
+func TestReplayTruncatedRecording(t *testing.T) {
+	group := fakeGroup("a", "b")
+	recording := recordedMessages(t, group, 3)
+	// Cut the last line short, as a crash while recording would.
+	truncated := recording[:len(recording)-10]
+	parties := map[string]tss.Party{"a": group["a"], "b": group["b"]}
+	err := ReplayFrom(strings.NewReader(truncated), parties)
+	if err == nil || !strings.Contains(err.Error(), "message 3") {
+		t.Fatalf("got %v, want an error reading message 3", err)
+	}
+	if got := group["b"].delivered(); got != 2 {
+		t.Fatalf("delivered %d messages, want the 2 before the cut", got)
+	}
+}
+
+func TestReplayCorruptRecording(t *testing.T) {
+	group := fakeGroup("a", "b")
+	lines := strings.SplitAfter(recordedMessages(t, group, 3), "\n")
+	lines[1] = "not a recorded message\n"
+	parties := map[string]tss.Party{"a": group["a"], "b": group["b"]}
+	if err := ReplayFrom(strings.NewReader(strings.Join(lines, "")), parties); err == nil {
+		t.Fatal("replayed a corrupt recording")
+	}
+	if got := group["b"].delivered(); got != 1 {
+		t.Fatalf("delivered %d messages, want the 1 before the corrupt line", got)
+	}
+}
@@ -0,0 +1,294 @@
+package dealer
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	eckeygen "github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	ecresharing "github.com/bnb-chain/tss-lib/v2/ecdsa/resharing"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// RefreshConfig describes an existing signer group being maintained rather
+// than a fresh deal.
+type RefreshConfig struct {
+	// Threshold is the group's t: any t+1 signers can sign. As for
+	// ImportConfig, it must be in [1, n-1].
+	Threshold int `json:"threshold" yaml:"threshold"`
+	// PreParamsDir caches pre-params for incoming parties, as in
+	// ImportConfig.
+	PreParamsDir string `json:"pre_params_dir" yaml:"pre_params_dir"`
+	// Curve, when set, is the curve the group is expected to be on; shares
+	// on any other curve are rejected with ErrCurveMismatch.
+	Curve string `json:"curve" yaml:"curve"`
+	// Timeout bounds how long the resharing may run. Zero means
+	// defaultResharingTimeout.
+	Timeout time.Duration `json:"timeout" yaml:"timeout"`
+}
+
+// ReplaceSigner issues a new share to newParty, which takes over lostIndex,
+// by resharing the key from the surviving signers to the survivors plus
+// newParty. t, n and the public key stay fixed, but every share is drawn
+// afresh, so the lost share no longer combines with the new ones and is
+// worthless even if it was stolen rather than destroyed.
+//
+// shares must be the share of every signer but the lost one: a survivor left
+// out would be left holding a share of the old sharing. The result holds
+// every signer's new share, newParty's included, in share ID order; the
+// survivors' old shares must be replaced with them.
+func ReplaceSigner(shares []eckeygen.LocalPartySaveData, lostIndex *big.Int, newParty PartyConfig, cfg RefreshConfig) ([]eckeygen.LocalPartySaveData, error) {
+	if cfg.Threshold < 1 {
+		return nil, fmt.Errorf("%w: threshold %d lets a single signer sign alone, it must be at least 1",
+			ErrInvalidConfig, cfg.Threshold)
+	}
+	if len(shares) < cfg.Threshold+1 {
+		return nil, fmt.Errorf("%w: need %d surviving shares to replace a signer, got %d",
+			ErrInvalidConfig, cfg.Threshold+1, len(shares))
+	}
+	if !lostIndex.IsInt64() || newParty.Index != lostIndex.Int64() {
+		return nil, fmt.Errorf("%w: replacement %s has index %d but the lost share has index %s",
+			ErrInvalidConfig, newParty.ID, newParty.Index, lostIndex)
+	}
+	if cfg.Curve != "" {
+		if err := checkShareCurve(SchemeECDSA, shares[0].ECDSAPub, SchemeECDSA, cfg.Curve); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := ComputePublicVerification(shares); err != nil {
+		return nil, err
+	}
+	ref := shares[0]
+	if len(shares) != len(ref.Ks)-1 {
+		return nil, fmt.Errorf("%w: the group has %d signers, so %d surviving shares are needed, got %d",
+			ErrInvalidConfig, len(ref.Ks), len(ref.Ks)-1, len(shares))
+	}
+	found := false
+	for _, k := range ref.Ks {
+		if k.Cmp(lostIndex) == 0 {
+			found = true
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: index %s is not part of the group", ErrInvalidConfig, lostIndex)
+	}
+	for _, s := range shares {
+		if s.ShareID.Cmp(lostIndex) == 0 {
+			return nil, fmt.Errorf("%w: share %s was passed as a survivor", ErrInvalidConfig, lostIndex)
+		}
+	}
+
+	pre, err := loadOrGeneratePreParams(cfg.PreParamsDir, newParty.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate pre-params for %s: %w", newParty.ID, err)
+	}
+	updated, err := reshareToReplacement(shares, newParty, pre, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// The new sharing must hold the same key, and agree on every signer's
+	// public share.
+	if _, err := ReconstructECDSAKey(updated); err != nil {
+		return nil, err
+	}
+	for _, s := range updated {
+		if !s.ECDSAPub.Equals(ref.ECDSAPub) {
+			return nil, fmt.Errorf("%w: share %s holds a different public key", ErrShareCorrupted, s.ShareID)
+		}
+	}
+	if _, err := ComputePublicVerification(updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// ReplaceStoredSigner replaces lostID, one of the signers of cfg's ECDSA
+// group, with newParty: it loads every other signer's share from store,
+// reshares them with ReplaceSigner and merges the new shares back with
+// UpdateShareSet, updating the deal's manifest to match. newParty takes
+// over lostID's index. Shares on another scheme or curve than cfg's are
+// rejected before anything else is done. cfg still lists lostID afterwards;
+// it is up to the caller to swap in newParty.
+func ReplaceStoredSigner(cfg *ImportConfig, store KeyStore, lostID string, newParty PartyConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	var lost *PartyConfig
+	var survivors []PartyConfig
+	for i, p := range cfg.Parties {
+		if p.ID == lostID {
+			lost = &cfg.Parties[i]
+		} else {
+			survivors = append(survivors, p)
+		}
+	}
+	if lost == nil {
+		return fmt.Errorf("%w: %s is not one of the group's signers", ErrInvalidConfig, lostID)
+	}
+	for _, p := range survivors {
+		if p.ID == newParty.ID {
+			return fmt.Errorf("%w: %s is already one of the group's signers", ErrInvalidConfig, newParty.ID)
+		}
+		if err := CheckShareCurve(store, p.ID, cfg); err != nil {
+			return err
+		}
+	}
+	if cfg.Scheme != SchemeECDSA {
+		return fmt.Errorf("%w: only ECDSA signers can be replaced", ErrInvalidConfig)
+	}
+	newParty.Index = lost.Index
+
+	shares := make([]eckeygen.LocalPartySaveData, len(survivors))
+	owners := make(map[string]string, len(survivors)+1)
+	for i, p := range survivors {
+		if err := LoadShareFrom(store, p.ID, &shares[i]); err != nil {
+			return err
+		}
+		owners[shares[i].ShareID.String()] = p.ID
+	}
+	owners[big.NewInt(lost.Index).String()] = newParty.ID
+
+	updated, err := ReplaceSigner(shares, big.NewInt(lost.Index), newParty, RefreshConfig{
+		Threshold:    cfg.Threshold,
+		PreParamsDir: cfg.PreParamsDir,
+		Curve:        cfg.Curve,
+		Timeout:      cfg.Timeout,
+	})
+	if err != nil {
+		return err
+	}
+	byID := make(map[string]eckeygen.LocalPartySaveData, len(updated))
+	for _, u := range updated {
+		id, ok := owners[u.ShareID.String()]
+		if !ok {
+			return fmt.Errorf("%w: resharing produced share ID %s, which no signer holds", ErrShareCorrupted, u.ShareID)
+		}
+		byID[id] = u
+	}
+	if err := UpdateShareSet(store, byID); err != nil {
+		return err
+	}
+	return replaceManifestParty(store, lostID, newParty)
+}
+
+// reshareToReplacement runs tss-lib's resharing from the survivors' shares
+// to the survivors plus newParty and returns the new shares in share ID
+// order.
+//
+// A tss-lib party sits in one committee, so each survivor runs twice: once
+// in the old committee and once in the new. Its old-committee party's key
+// is its share ID plus the curve order N: the two are congruent mod N, so
+// the Lagrange coefficients tss-lib derives from them are unchanged, but the
+// party is told apart from the survivor's new-committee party.
+func reshareToReplacement(shares []eckeygen.LocalPartySaveData, newParty PartyConfig, pre *eckeygen.LocalPreParams, cfg RefreshConfig) ([]eckeygen.LocalPartySaveData, error) {
+	survivors := append([]eckeygen.LocalPartySaveData(nil), shares...)
+	sort.Slice(survivors, func(i, j int) bool { return survivors[i].ShareID.Cmp(survivors[j].ShareID) < 0 })
+	curve := survivors[0].ECDSAPub.Curve()
+	order := curve.Params().N
+
+	oldIDs := make([]*tss.PartyID, len(survivors))
+	newIDs := make([]*tss.PartyID, len(survivors), len(survivors)+1)
+	for i, s := range survivors {
+		oldIDs[i] = tss.NewPartyID(fmt.Sprintf("share-%s/old", s.ShareID), "", new(big.Int).Add(s.ShareID, order))
+		newIDs[i] = tss.NewPartyID(fmt.Sprintf("share-%s", s.ShareID), "", s.ShareID)
+	}
+	replacement := newParty.partyID()
+	newIDs = append(newIDs, replacement)
+	oldCtx := tss.NewPeerContext(tss.SortPartyIDs(oldIDs))
+	newCtx := tss.NewPeerContext(tss.SortPartyIDs(newIDs))
+	params := func(pid *tss.PartyID) *tss.ReSharingParameters {
+		return tss.NewReSharingParameters(curve, oldCtx, newCtx, pid,
+			len(oldIDs), cfg.Threshold, len(newIDs), cfg.Threshold)
+	}
+
+	// The old-committee parties know the group under the aliased keys; the
+	// new-committee parties only need that public view and their own
+	// pre-params.
+	view := aliasedSaveData(survivors[0], order)
+	view.LocalSecrets = eckeygen.LocalSecrets{}
+
+	partyMap := make(map[string]tss.Party)
+	rt := newRouter(partyMap, &ImportConfig{Scheme: SchemeECDSA})
+	defer rt.Close()
+	endCh := make(chan partyResult, len(oldIDs)+len(newIDs))
+	var parties []tss.Party
+	for i, s := range survivors {
+		oldSave := aliasedSaveData(s, order)
+		newSave := view
+		newSave.LocalPreParams = s.LocalPreParams
+		for _, p := range []struct {
+			pid  *tss.PartyID
+			save eckeygen.LocalPartySaveData
+		}{{oldIDs[i], oldSave}, {newIDs[i], newSave}} {
+			party := ecresharing.NewLocalParty(params(p.pid), p.save, rt.outCh(p.pid), makeEcEndCh(p.pid, endCh, rt.done))
+			partyMap[p.pid.Id] = party
+			parties = append(parties, party)
+		}
+	}
+	newSave := view
+	newSave.LocalPreParams = *pre
+	party := ecresharing.NewLocalParty(params(replacement), newSave, rt.outCh(replacement), makeEcEndCh(replacement, endCh, rt.done))
+	partyMap[replacement.Id] = party
+	parties = append(parties, party)
+
+	errCh := make(chan error, len(parties))
+	for _, p := range parties {
+		go func(p tss.Party) {
+			if err := p.Start(); err != nil {
+				errCh <- fmt.Errorf("resharing party %s failed: %v", p.PartyID().Id, err)
+			}
+		}(p)
+	}
+	rt.start()
+
+	isNew := make(map[string]bool, len(newIDs))
+	for _, pid := range newIDs {
+		isNew[pid.Id] = true
+	}
+	results := map[string]partyResult{}
+	oldDone := 0
+	timeout := defaultResharingTimeout
+	if cfg.Timeout > 0 {
+		timeout = cfg.Timeout
+	}
+	deadline := time.After(timeout)
+	for len(results) < len(newIDs) || oldDone < len(oldIDs) {
+		select {
+		case r := <-endCh:
+			if isNew[r.pid.Id] {
+				results[r.pid.Id] = r
+			} else {
+				oldDone++
+			}
+		case err := <-errCh:
+			return nil, err
+		case <-deadline:
+			return nil, fmt.Errorf("%w: only %d of %d signers received a new share within %s",
+				ErrResharingTimeout, len(results), len(newIDs), timeout)
+		}
+	}
+
+	updated := make([]eckeygen.LocalPartySaveData, 0, len(results))
+	for _, r := range sortResults(results) {
+		updated = append(updated, r.data.(eckeygen.LocalPartySaveData))
+	}
+	return updated, nil
+}
+
+// aliasedSaveData returns a copy of save whose share IDs, its own included,
+// are offset by order, with a copy of the secret share: the resharing zeroes
+// the old committee's. The caller's save data is left untouched.
+func aliasedSaveData(save eckeygen.LocalPartySaveData, order *big.Int) eckeygen.LocalPartySaveData {
+	ks := make([]*big.Int, len(save.Ks))
+	for i, k := range save.Ks {
+		ks[i] = new(big.Int).Add(k, order)
+	}
+	save.Ks = ks
+	save.LocalSecrets = eckeygen.LocalSecrets{
+		Xi:      new(big.Int).Set(save.Xi),
+		ShareID: new(big.Int).Add(save.ShareID, order),
+	}
+	return save
+}
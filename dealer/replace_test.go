@@ -0,0 +1,75 @@
+package dealer
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	eckeygen "github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+)
+
+// dealECDSA imports a fresh key to cfg's group and returns the shares and a
+// copy of the key.
+func dealECDSA(t *testing.T, cfg *ImportConfig) ([]eckeygen.LocalPartySaveData, *big.Int) {
+	t.Helper()
+	key := testKey(t, cfg)
+	want := new(big.Int).Set(key)
+	res, err := ImportECDSAKey(cfg, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.ECDSAShares, want
+}
+
+// without returns shares minus the one with share ID id.
+func without(shares []eckeygen.LocalPartySaveData, id int64) []eckeygen.LocalPartySaveData {
+	var rest []eckeygen.LocalPartySaveData
+	for _, s := range shares {
+		if s.ShareID.Cmp(big.NewInt(id)) != 0 {
+			rest = append(rest, s)
+		}
+	}
+	return rest
+}
+
+func TestReplaceSignerRejectsZeroThreshold(t *testing.T) {
+	_, err := ReplaceSigner(nil, big.NewInt(1), PartyConfig{ID: "x", Index: 1}, RefreshConfig{Threshold: 0})
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("got %v, want ErrInvalidConfig", err)
+	}
+}
+
+func TestReplaceSignerReshares(t *testing.T) {
+	skipIfShort(t)
+	cfg := testConfig(SchemeECDSA, 1, 3)
+	shares, key := dealECDSA(t, cfg)
+	lost := shares[1]
+
+	updated, err := ReplaceSigner(without(shares, 2), big.NewInt(2),
+		PartyConfig{ID: "signer2b", Moniker: "Signer2b", Index: 2},
+		RefreshConfig{Threshold: 1, PreParamsDir: testPreParamsDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updated) != 3 {
+		t.Fatalf("got %d shares, want 3", len(updated))
+	}
+	if err := VerifyAllQuorums(updated, 1, key); err != nil {
+		t.Fatal(err)
+	}
+	for i, s := range updated {
+		if s.Xi.Cmp(shares[i].Xi) == 0 {
+			t.Errorf("share %s was not redrawn", s.ShareID)
+		}
+	}
+
+	// The lost share must be useless alongside the new ones.
+	got, err := interpolateAt([]*big.Int{updated[0].ShareID, lost.ShareID},
+		[]*big.Int{updated[0].Xi, lost.Xi}, big.NewInt(0), lost.ECDSAPub.Curve())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Cmp(key) == 0 {
+		t.Fatal("the lost share still combines with a new share")
+	}
+}
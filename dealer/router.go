@@ -0,0 +1,282 @@
+package dealer
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// router is a simple in-process broadcast router: it sends each outgoing
+// message to every recipient named in its routing by feeding the wire bytes
+// to that party's local instance.
+type router struct {
+	parties map[string]tss.Party
+
+	// deliver, when set, decides whether a message from one party reaches
+	// another; undelivered messages are silently dropped.
+	deliver func(from, to *tss.PartyID) bool
+
+	// logger, when set, is told about every message routed, dropped or
+	// rejected.
+	logger *log.Logger
+
+	// recorder, when set, captures each delivery before it is made.
+	recorder *MessageRecorder
+
+	// seen, when set, holds the digests of the messages already routed, so
+	// exact duplicates are dropped.
+	seen map[[sha256.Size]byte]bool
+	// duplicate makes every message get queued twice.
+	duplicate bool
+
+	// inBothGroups holds parties that are in the old and the new group at
+	// once. They do get the messages they address to themselves, since those
+	// go from their role in one group to their role in the other.
+	inBothGroups map[string]bool
+
+	// progress, when set, is told the first time each party sends a message
+	// of a new round.
+	progress    func(partyID string, round, total int)
+	totalRounds int
+	rounds      map[string]int
+
+	// queue holds the messages waiting to be routed, and ready is signalled
+	// when it gains some. It is unbounded so that a party sending a whole
+	// round's messages from inside UpdateFromBytes, on the routing goroutine,
+	// never waits for that same goroutine to make room.
+	mu    sync.Mutex
+	queue []msg
+	ready chan struct{}
+
+	// done is closed by Close to stop the router and the end channel
+	// forwarders; stopped is closed once the routing goroutine has exited,
+	// to stop the outgoing message forwarders.
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+func newRouter(parties map[string]tss.Party, cfg *ImportConfig) *router {
+	rt := &router{
+		parties:      parties,
+		deliver:      cfg.Deliver,
+		logger:       cfg.Logger,
+		recorder:     cfg.Recorder,
+		inBothGroups: make(map[string]bool),
+		progress:     cfg.Progress,
+		totalRounds:  ExpectedRounds(cfg.Scheme),
+		rounds:       make(map[string]int),
+		duplicate:    cfg.duplicateMessages,
+		ready:        make(chan struct{}, 1),
+		done:         make(chan struct{}),
+		stopped:      make(chan struct{}),
+	}
+	if cfg.importerIsSigner() {
+		rt.inBothGroups[cfg.Importer.ID] = true
+	}
+	if cfg.DedupMessages {
+		rt.seen = make(map[[sha256.Size]byte]bool)
+	}
+	return rt
+}
+
+// outCh returns the channel pid sends its outgoing messages on. They are
+// queued for routing as they arrive, so sends on it never wait for routing.
+func (rt *router) outCh(pid *tss.PartyID) chan tss.Message {
+	ch := make(chan tss.Message, 10)
+	go func() {
+		for {
+			select {
+			case m := <-ch:
+				rt.enqueue(msg{from: pid, data: m})
+			case <-rt.stopped:
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// enqueue adds m to the routing queue.
+func (rt *router) enqueue(m msg) {
+	rt.mu.Lock()
+	rt.queue = append(rt.queue, m)
+	if rt.duplicate {
+		rt.queue = append(rt.queue, m)
+	}
+	rt.mu.Unlock()
+	select {
+	case rt.ready <- struct{}{}:
+	default:
+	}
+}
+
+// start routes queued messages in the background until Close.
+func (rt *router) start() {
+	rt.wg.Add(1)
+	go func() {
+		defer rt.wg.Done()
+		rt.run()
+	}()
+}
+
+// run routes queued messages, in the order they were sent, until the router
+// is closed.
+func (rt *router) run() {
+	for {
+		select {
+		case <-rt.ready:
+		case <-rt.done:
+			return
+		}
+		rt.mu.Lock()
+		batch := rt.queue
+		rt.queue = nil
+		rt.mu.Unlock()
+		for _, m := range batch {
+			select {
+			case <-rt.done:
+				return
+			default:
+			}
+			if err := rt.route(m); err != nil {
+				rt.logf("Rejected message: %v", err)
+			}
+		}
+	}
+}
+
+// Close stops the router and the forwarders feeding it, and waits for the
+// routing goroutine to exit. It must be called once the resharing is over,
+// however it ended, or those goroutines leak. Calling it more than once is
+// safe.
+func (rt *router) Close() error {
+	rt.closeOnce.Do(func() {
+		close(rt.done)
+		// Keep taking messages until routing has stopped, so a party
+		// sending from inside a delivery still in progress isn't stuck.
+		rt.wg.Wait()
+		close(rt.stopped)
+	})
+	rt.wg.Wait()
+	return nil
+}
+
+// logf writes a line to the router's logger, if it has one.
+func (rt *router) logf(format string, args ...interface{}) {
+	if rt.logger != nil {
+		rt.logger.Printf(format, args...)
+	}
+}
+
+// route delivers m to its recipients. A message whose sender isn't one of
+// the known parties is rejected before anything is delivered.
+func (rt *router) route(m msg) error {
+	if err := rt.checkSender(m.from); err != nil {
+		return err
+	}
+	payload, routing, err := m.data.WireBytes()
+	if err != nil {
+		return fmt.Errorf("failed to serialize message from %s: %v", m.from.Id, err)
+	}
+	if rt.seen != nil {
+		digest := messageDigest(m.from, routing, messageRound(m.data.Type()), payload)
+		if rt.seen[digest] {
+			rt.logf(">>> Dropping duplicate %s from %s", m.data.Type(), m.from.Id)
+			return nil
+		}
+		rt.seen[digest] = true
+	}
+	rt.logf(">>> %s sending message to all parties: %s", m.from.Id, m.data.Type())
+	rt.reportProgress(m)
+	for _, to := range routing.To {
+		if to.Id == m.from.Id && !rt.inBothGroups[to.Id] {
+			rt.logf("Ignoring message from self: %s", m.from.Id)
+			continue
+		}
+		if rt.deliver != nil && !rt.deliver(m.from, to) {
+			rt.logf(">>> Dropping message from %s to %s", m.from.Id, to.Id)
+			continue
+		}
+		p := rt.parties[to.Id]
+		if p == nil {
+			rt.logf("Party instance for %s not found", to.Id)
+			continue
+		}
+		if rt.recorder != nil {
+			if err := rt.recorder.record(m.from, to, payload, routing.IsBroadcast); err != nil {
+				rt.logf("Error recording message from %s to %s: %v", m.from.Id, to.Id, err)
+			}
+		}
+		ok, err := p.UpdateFromBytes(payload, m.from, routing.IsBroadcast)
+		if err != nil {
+			rt.logf("Error updating party %s with message from %s: %v", to.Id, m.from.Id, err)
+		}
+		if !ok {
+			rt.logf("Party %s could not process message from %s: %v", to.Id, m.from.Id, err)
+		}
+		rt.logf(">>> %s updated party %s with message", m.from.Id, to.Id)
+	}
+	return nil
+}
+
+// checkSender makes sure from is a known party: its id is routed to a local
+// instance and the key matches that instance's, so a message can't claim to
+// come from a party it doesn't.
+func (rt *router) checkSender(from *tss.PartyID) error {
+	if from == nil {
+		return fmt.Errorf("%w: message has no sender", ErrUnknownParty)
+	}
+	p := rt.parties[from.Id]
+	if p == nil {
+		return fmt.Errorf("%w: message from %s", ErrUnknownParty, from.Id)
+	}
+	if p.PartyID().KeyInt().Cmp(from.KeyInt()) != 0 {
+		return fmt.Errorf("%w: message from %s carries the wrong key", ErrUnknownParty, from.Id)
+	}
+	return nil
+}
+
+// messageDigest identifies a message by its sender, recipients, round and
+// payload. The recipients matter: with NoProofFac, tss-lib sends each new
+// party a byte-identical DGRound4Message1, and those aren't duplicates. Each
+// field is length-prefixed so different splits can't collide.
+func messageDigest(from *tss.PartyID, routing *tss.MessageRouting, round int, payload []byte) [sha256.Size]byte {
+	fields := [][]byte{[]byte(from.Id), from.Key, []byte(strconv.Itoa(round)),
+		[]byte(strconv.FormatBool(routing.IsBroadcast)), []byte(strconv.Itoa(len(routing.To)))}
+	for _, to := range routing.To {
+		fields = append(fields, []byte(to.Id), to.Key)
+	}
+	fields = append(fields, payload)
+
+	h := sha256.New()
+	for _, field := range fields {
+		var n [8]byte
+		binary.BigEndian.PutUint64(n[:], uint64(len(field)))
+		h.Write(n[:])
+		h.Write(field)
+	}
+	var digest [sha256.Size]byte
+	copy(digest[:], h.Sum(nil))
+	return digest
+}
+
+// reportProgress tells the progress callback when m is the first message its
+// sender sent in a new round.
+func (rt *router) reportProgress(m msg) {
+	if rt.progress == nil {
+		return
+	}
+	round := messageRound(m.data.Type())
+	if round <= rt.rounds[m.from.Id] {
+		return
+	}
+	rt.rounds[m.from.Id] = round
+	rt.progress(m.from.Id, round, rt.totalRounds)
+}
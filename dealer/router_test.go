@@ -0,0 +1,267 @@
+package dealer
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	edkeygen "github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestEndChannelsDontWaitForConsumer(t *testing.T) {
+	const n = 5
+	endCh := make(chan partyResult, n)
+	done := make(chan struct{})
+	defer close(done)
+
+	// Nothing reads endCh: every party must still hand over its result.
+	for i := 0; i < n; i++ {
+		ch := makeEdEndCh(PartyConfig{ID: "p", Index: int64(i + 1)}.partyID(), endCh, done)
+		select {
+		case ch <- &edkeygen.LocalPartySaveData{}:
+		case <-time.After(time.Second):
+			t.Fatalf("party %d blocked reporting its result", i)
+		}
+	}
+	deadline := time.After(5 * time.Second)
+	for len(endCh) < n {
+		select {
+		case <-deadline:
+			t.Fatalf("only %d of %d results forwarded", len(endCh), n)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestImportWithSlowConsumer(t *testing.T) {
+	skipIfShort(t)
+	cfg := testConfig(SchemeEDDSA, 2, 4)
+	total := ExpectedRounds(cfg.Scheme)
+	cfg.Progress = func(partyID string, round, _ int) {
+		// The collector reports each completed party; dawdle there.
+		if round == total {
+			time.Sleep(200 * time.Millisecond)
+		}
+	}
+	res, err := ImportEdDSAKey(cfg, testKey(t, cfg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.EdDSAShares) != cfg.PartyCount {
+		t.Fatalf("got %d shares, want %d", len(res.EdDSAShares), cfg.PartyCount)
+	}
+}
+
+func TestImportManySigners(t *testing.T) {
+	skipIfShort(t)
+	// Each party sends a whole round of messages from inside the delivery
+	// that completes the previous one, far more than any fixed buffer holds.
+	cfg := testConfig(SchemeEDDSA, 16, 25)
+	res, err := ImportEdDSAKey(cfg, testKey(t, cfg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Complete || len(res.EdDSAShares) != cfg.PartyCount {
+		t.Fatalf("complete %v with %d shares, want %d", res.Complete, len(res.EdDSAShares), cfg.PartyCount)
+	}
+}
+
+func TestRouterCloseDoesNotHang(t *testing.T) {
+	rt := newRouter(map[string]tss.Party{}, testConfig(SchemeEDDSA, 1, 2))
+	rt.outCh(PartyConfig{ID: "p", Index: 1}.partyID())
+	rt.start()
+	closed := make(chan struct{})
+	go func() {
+		rt.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close hung")
+	}
+}
+
+func TestValidateRejectsTooManyParties(t *testing.T) {
+	cfg := testConfig(SchemeEDDSA, 1, MaxPartyCount+1)
+	if err := cfg.Validate(); !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("got %v, want ErrInvalidConfig", err)
+	}
+}
+
+// fakeParty is a tss.Party that only counts the messages delivered to it.
+type fakeParty struct {
+	tss.Party
+	pid *tss.PartyID
+
+	mu  sync.Mutex
+	got int
+}
+
+func (p *fakeParty) PartyID() *tss.PartyID { return p.pid }
+
+func (p *fakeParty) UpdateFromBytes([]byte, *tss.PartyID, bool) (bool, *tss.Error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.got++
+	return true, nil
+}
+
+func (p *fakeParty) delivered() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.got
+}
+
+// fakeMessage is a tss.Message with a fixed type, payload and routing.
+type fakeMessage struct {
+	tss.Message
+	typ     string
+	payload []byte
+	routing *tss.MessageRouting
+}
+
+func (m fakeMessage) Type() string { return m.typ }
+
+func (m fakeMessage) WireBytes() ([]byte, *tss.MessageRouting, error) {
+	return m.payload, m.routing, nil
+}
+
+// fakeGroup returns a fake party for each of ids, by id.
+func fakeGroup(ids ...string) map[string]*fakeParty {
+	group := map[string]*fakeParty{}
+	for i, id := range ids {
+		group[id] = &fakeParty{pid: PartyConfig{ID: id, Moniker: id, Index: int64(i + 1)}.partyID()}
+	}
+	return group
+}
+
+func routerFor(group map[string]*fakeParty, cfg *ImportConfig) *router {
+	parties := map[string]tss.Party{}
+	for id, p := range group {
+		parties[id] = p
+	}
+	return newRouter(parties, cfg)
+}
+
+func TestRouterDropsDuplicates(t *testing.T) {
+	group := fakeGroup("a", "b", "c")
+	cfg := testConfig(SchemeEDDSA, 1, 2)
+	cfg.DedupMessages = true
+	rt := routerFor(group, cfg)
+
+	bcast := fakeMessage{
+		typ:     "binance.tsslib.eddsa.resharing.DGRound1Message",
+		payload: []byte("commitment"),
+		routing: &tss.MessageRouting{From: group["a"].pid, To: []*tss.PartyID{group["b"].pid, group["c"].pid}, IsBroadcast: true},
+	}
+	for i := 0; i < 2; i++ {
+		if err := rt.route(msg{from: group["a"].pid, data: bcast}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if group["b"].delivered() != 1 || group["c"].delivered() != 1 {
+		t.Fatalf("delivered %d and %d times, want once each", group["b"].delivered(), group["c"].delivered())
+	}
+}
+
+func TestRouterKeepsIdenticalPayloadsToDifferentRecipients(t *testing.T) {
+	group := fakeGroup("a", "b", "c")
+	cfg := testConfig(SchemeEDDSA, 1, 2)
+	cfg.DedupMessages = true
+	rt := routerFor(group, cfg)
+
+	// As NoProofFac's DGRound4Message1: the same bytes to each new party.
+	for _, to := range []string{"b", "c"} {
+		m := fakeMessage{
+			typ:     "binance.tsslib.ecdsa.resharing.DGRound4Message1",
+			payload: []byte("no proof"),
+			routing: &tss.MessageRouting{From: group["a"].pid, To: []*tss.PartyID{group[to].pid}},
+		}
+		if err := rt.route(msg{from: group["a"].pid, data: m}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if group["b"].delivered() != 1 || group["c"].delivered() != 1 {
+		t.Fatalf("delivered %d and %d times, want once each", group["b"].delivered(), group["c"].delivered())
+	}
+}
+
+func TestImportWithDuplicatedDeliveries(t *testing.T) {
+	skipIfShort(t)
+	// NoProofFac is on, so this also checks the identical point-to-point
+	// messages it produces aren't dropped as duplicates.
+	cfg := testConfig(SchemeECDSA, 2, 4)
+	cfg.DedupMessages = true
+	cfg.duplicateMessages = true
+	res, err := ImportECDSAKey(cfg, testKey(t, cfg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Complete || len(res.ECDSAShares) != cfg.PartyCount {
+		t.Fatalf("complete %v with %d shares, want %d", res.Complete, len(res.ECDSAShares), cfg.PartyCount)
+	}
+}
+
+func TestRouterRejectsUnknownSender(t *testing.T) {
+	group := fakeGroup("a", "b")
+	rt := routerFor(group, testConfig(SchemeEDDSA, 1, 2))
+	impostor := PartyConfig{ID: "mallory", Moniker: "mallory", Index: 9}.partyID()
+	wrongKey := PartyConfig{ID: "a", Moniker: "a", Index: 9}.partyID()
+
+	for name, from := range map[string]*tss.PartyID{"unregistered": impostor, "wrong key": wrongKey, "no sender": nil} {
+		m := fakeMessage{
+			typ:     "binance.tsslib.eddsa.resharing.DGRound1Message",
+			payload: []byte(name),
+			routing: &tss.MessageRouting{From: from, To: []*tss.PartyID{group["a"].pid, group["b"].pid}, IsBroadcast: true},
+		}
+		if err := rt.route(msg{from: from, data: m}); !errors.Is(err, ErrUnknownParty) {
+			t.Errorf("%s: got %v, want ErrUnknownParty", name, err)
+		}
+	}
+	if group["a"].delivered() != 0 || group["b"].delivered() != 0 {
+		t.Fatalf("delivered %d and %d messages, want none", group["a"].delivered(), group["b"].delivered())
+	}
+}
+
+func TestRouterDeliverPredicate(t *testing.T) {
+	group := fakeGroup("a", "b", "c")
+	cfg := testConfig(SchemeEDDSA, 1, 2)
+	cfg.Deliver = func(from, to *tss.PartyID) bool { return to.Id != "c" }
+	rt := routerFor(group, cfg)
+
+	m := fakeMessage{
+		typ:     "binance.tsslib.eddsa.resharing.DGRound1Message",
+		payload: []byte("commitment"),
+		routing: &tss.MessageRouting{From: group["a"].pid, To: []*tss.PartyID{group["b"].pid, group["c"].pid}, IsBroadcast: true},
+	}
+	if err := rt.route(msg{from: group["a"].pid, data: m}); err != nil {
+		t.Fatal(err)
+	}
+	if group["b"].delivered() != 1 || group["c"].delivered() != 0 {
+		t.Fatalf("delivered %d and %d times, want once to b only", group["b"].delivered(), group["c"].delivered())
+	}
+}
+
+func TestImportWithPartitionedSignerTimesOut(t *testing.T) {
+	skipIfShort(t)
+	cfg := testConfig(SchemeECDSA, 1, 3)
+	cfg.Timeout = 20 * time.Second
+	cfg.Deliver = func(from, to *tss.PartyID) bool {
+		return from.Id != "signer3" && to.Id != "signer3"
+	}
+	res, err := ImportECDSAKey(cfg, testKey(t, cfg))
+	if !errors.Is(err, ErrResharingTimeout) {
+		t.Fatalf("got %v, want ErrResharingTimeout", err)
+	}
+	if res == nil || res.Complete {
+		t.Fatalf("got result %+v, want an incomplete one", res)
+	}
+	for _, s := range res.ECDSAShares {
+		if s.ShareID.Int64() == cfg.Parties[2].Index {
+			t.Fatal("the partitioned signer produced a share")
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package dealer
+
+import (
+	"crypto/elliptic"
+	"fmt"
+	"math/big"
+
+	tsscrypto "github.com/bnb-chain/tss-lib/v2/crypto"
+	eckeygen "github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	edkeygen "github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// BuildImporterSaveData builds the save data of the importer as the sole
+// member of a 1-of-1 group holding key, in the shape tss-lib's keygen would
+// have left it: a single share with ID pid's key, whose public share is the
+// group's public key. For SchemeECDSA the result is an
+// eckeygen.LocalPartySaveData carrying pre; for SchemeEDDSA it is an
+// edkeygen.LocalPartySaveData and pre is ignored.
+//
+// The save data's Xi is key itself, not a copy: the importer's resharing
+// party zeroes it once the key is dealt.
+func BuildImporterSaveData(scheme Scheme, key *big.Int, pre *eckeygen.LocalPreParams, pid *tss.PartyID, curve elliptic.Curve) (interface{}, error) {
+	if pid == nil {
+		return nil, fmt.Errorf("%w: importer has no party ID", ErrInvalidConfig)
+	}
+	if !InRange(key, curve) {
+		return nil, fmt.Errorf("%w: key must be in [1, N-1] for the importer's curve", ErrInvalidKeyRange)
+	}
+	pub := tsscrypto.ScalarBaseMult(curve, key)
+
+	switch scheme {
+	case SchemeECDSA:
+		if pre == nil || pre.PaillierSK == nil || !pre.ValidateWithProof() {
+			return nil, fmt.Errorf("%w: importer %s has no valid pre-params", ErrInvalidConfig, pid.Id)
+		}
+		save := eckeygen.NewLocalPartySaveData(1)
+		save.LocalPreParams = *pre
+		save.LocalSecrets = eckeygen.LocalSecrets{
+			Xi:      key,
+			ShareID: pid.KeyInt(),
+		}
+		save.Ks[0] = pid.KeyInt()
+		save.BigXj[0] = pub
+		save.ECDSAPub = pub
+		save.NTildej[0] = pre.NTildei
+		save.H1j[0] = pre.H1i
+		save.H2j[0] = pre.H2i
+		save.PaillierPKs[0] = &pre.PaillierSK.PublicKey
+		return save, nil
+	case SchemeEDDSA:
+		save := edkeygen.NewLocalPartySaveData(1)
+		save.LocalSecrets = edkeygen.LocalSecrets{
+			Xi:      key,
+			ShareID: pid.KeyInt(),
+		}
+		save.Ks[0] = pid.KeyInt()
+		save.BigXj[0] = pub
+		save.EDDSAPub = pub
+		return save, nil
+	}
+	return nil, fmt.Errorf("%w: unknown scheme %q", ErrInvalidConfig, scheme)
+}
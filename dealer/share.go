@@ -0,0 +1,310 @@
+package dealer
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/nacl/box"
+)
+
+// Output formats for dealt shares.
+const (
+	// OutputFiles writes one share file per signer into the output directory.
+	OutputFiles = "files"
+	// OutputStdout writes one "<party id> <base64 share>" line per signer to
+	// stdout, for capturing shares in containerized workflows, sealed to the
+	// configured OutputRecipient if there is one. All progress output goes
+	// to stderr so stdout carries nothing but shares.
+	OutputStdout = "stdout"
+)
+
+// partyShare pairs a signer's save data with the party it belongs to and
+// the share ID that party's share must carry.
+type partyShare struct {
+	id      string
+	shareID *big.Int
+	data    interface{}
+}
+
+// SaveShare writes a party's save data to <dir>/<partyID>.json, readable
+// only by the current user. It is SaveShareTo with a FileKeyStore.
+func SaveShare(dir, partyID string, data interface{}) error {
+	return SaveShareTo(FileKeyStore{Dir: dir}, partyID, data)
+}
+
+// writeFileAtomic writes bz to a temporary file next to path and renames it
+// into place, so path is either absent or complete, never half written.
+func writeFileAtomic(path string, bz []byte, perm os.FileMode) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	defer os.Remove(tmp) // no-op once renamed
+
+	if err := f.Chmod(perm); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(bz); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// LoadShare reads save data written by SaveShare into data.
+func LoadShare(dir, partyID string, data interface{}) error {
+	return LoadShareFrom(FileKeyStore{Dir: dir}, partyID, data)
+}
+
+// WriteShareLine writes a party's save data to w as a single
+// "<party id> <base64 JSON>" line.
+func WriteShareLine(w io.Writer, partyID string, data interface{}) error {
+	bz, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to serialize share for %s: %v", partyID, err)
+	}
+	defer wipeBytes(bz)
+	_, err = fmt.Fprintf(w, "%s %s\n", partyID, base64.StdEncoding.EncodeToString(bz))
+	return err
+}
+
+// WriteSealedShareLine is WriteShareLine with the JSON sealed to an
+// anonymous NaCl box for to, so only the holder of its private key can read
+// the share.
+func WriteSealedShareLine(w io.Writer, partyID string, data interface{}, to RecipientPubKey) error {
+	bz, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to serialize share for %s: %v", partyID, err)
+	}
+	sealed, err := box.SealAnonymous(nil, bz, (*[32]byte)(&to), rand.Reader)
+	wipeBytes(bz)
+	if err != nil {
+		return fmt.Errorf("failed to seal share for %s: %v", partyID, err)
+	}
+	_, err = fmt.Fprintf(w, "%s %s\n", partyID, base64.StdEncoding.EncodeToString(sealed))
+	return err
+}
+
+// ReadShareLine decodes a line written by WriteShareLine, or by
+// WriteSealedShareLine when priv is set, into data and returns its party
+// id.
+func ReadShareLine(line string, priv *RecipientPrivKey, data interface{}) (string, error) {
+	partyID, bz, err := openShareLine(line, priv)
+	if err != nil {
+		return "", err
+	}
+	defer wipeBytes(bz)
+	if err := json.Unmarshal(bz, data); err != nil {
+		return "", fmt.Errorf("%w: failed to parse share %s: %v", ErrShareCorrupted, partyID, err)
+	}
+	return partyID, nil
+}
+
+// openShareLine splits a share line into its party id and the share's JSON,
+// opening the share with priv when it is set.
+func openShareLine(line string, priv *RecipientPrivKey) (string, []byte, error) {
+	partyID, encoded, ok := strings.Cut(strings.TrimSpace(line), " ")
+	if !ok || partyID == "" {
+		return "", nil, fmt.Errorf("%w: share line is not \"<party id> <share>\"", ErrShareCorrupted)
+	}
+	bz, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: share line for %s: %v", ErrShareCorrupted, partyID, err)
+	}
+	if priv == nil {
+		return partyID, bz, nil
+	}
+	pub, err := priv.publicKey()
+	if err != nil {
+		return "", nil, err
+	}
+	key := [32]byte(*priv)
+	opened, ok := box.OpenAnonymous(nil, bz, (*[32]byte)(&pub), &key)
+	wipeBytes(key[:])
+	if !ok {
+		return "", nil, fmt.Errorf("%w: share line for %s can't be opened with this key", ErrShareCorrupted, partyID)
+	}
+	return partyID, opened, nil
+}
+
+// writeShares emits the dealt shares, followed by the deal's manifest, in
+// the configured output format.
+func writeShares(cfg *ImportConfig, shares []partyShare, manifest Manifest) error {
+	shares = append(shares[:len(shares):len(shares)], partyShare{id: ManifestID, data: manifest})
+	switch cfg.OutputFormat {
+	case OutputStdout:
+		to, err := cfg.outputRecipient()
+		if err != nil {
+			return err
+		}
+		for _, s := range shares {
+			if to != nil {
+				err = WriteSealedShareLine(os.Stdout, s.id, s.data, *to)
+			} else {
+				err = WriteShareLine(os.Stdout, s.id, s.data)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	case "", OutputFiles:
+		if cfg.Store != nil {
+			return putShares(cfg.Store, shares)
+		}
+		if cfg.OutputDir == "" {
+			return nil
+		}
+		return publishShares(cfg.OutputDir, shares)
+	}
+	return nil
+}
+
+// checkOutputFree fails with ErrOutputExists when any signer's share file,
+// or a manifest, is already in the output directory, unless cfg.Force is
+// set. It runs before
+// the deal so a rerun doesn't redo the expensive work only to be refused.
+func checkOutputFree(cfg *ImportConfig) error {
+	if cfg.Force || (cfg.OutputFormat != "" && cfg.OutputFormat != OutputFiles) {
+		return nil
+	}
+	ids := []string{ManifestID}
+	for _, p := range cfg.Parties {
+		ids = append(ids, p.ID)
+	}
+	if cfg.Store != nil {
+		for _, id := range ids {
+			_, err := cfg.Store.GetShare(id)
+			if err == nil {
+				return fmt.Errorf("%w: the store already holds a share for %s", ErrOutputExists, id)
+			}
+			if !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+		}
+		return nil
+	}
+	if cfg.OutputDir == "" {
+		return nil
+	}
+	for _, id := range ids {
+		path := shareFile(cfg.OutputDir, id)
+		_, err := os.Stat(path)
+		if err == nil {
+			return fmt.Errorf("%w: %s (use -force to overwrite)", ErrOutputExists, path)
+		}
+		if !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// publishShares writes every share into a staging directory inside dir and
+// only then moves them into place, so an interrupted or failed write never
+// leaves a partial deal behind. Existing share files are replaced, each in a
+// single rename, and kept aside until every rename has succeeded: if moving
+// fails part way, the files already replaced are restored and the new ones
+// removed again.
+func publishShares(dir string, shares []partyShare) (err error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	staging, err := os.MkdirTemp(dir, ".staging-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(staging)
+
+	if err := putShares(FileKeyStore{Dir: staging}, shares); err != nil {
+		return err
+	}
+	kept := make(map[string]bool, len(shares))
+	for _, s := range shares {
+		err := os.Link(shareFile(dir, s.id), shareFile(staging, s.id)+".old")
+		if err == nil {
+			kept[s.id] = true
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	published := make([]string, 0, len(shares))
+	defer func() {
+		if err != nil {
+			for _, id := range published {
+				if kept[id] {
+					os.Rename(shareFile(staging, id)+".old", shareFile(dir, id))
+				} else {
+					os.Remove(shareFile(dir, id))
+				}
+			}
+		}
+	}()
+	for _, s := range shares {
+		if err := os.Rename(shareFile(staging, s.id), shareFile(dir, s.id)); err != nil {
+			return err
+		}
+		published = append(published, s.id)
+	}
+	return nil
+}
+
+// putShares stores every share in store and then checks each one, but the
+// manifest, reads back as its party's.
+func putShares(store KeyStore, shares []partyShare) error {
+	for _, s := range shares {
+		if err := SaveShareTo(store, s.id, s.data); err != nil {
+			return err
+		}
+	}
+	for _, s := range shares {
+		if s.id == ManifestID {
+			continue
+		}
+		if err := checkShareOwner(store, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkShareOwner reloads s from store and makes sure it holds s's share
+// rather than another party's, so a routing or indexing slip that
+// cross-wires shares fails the deal instead of handing a signer someone
+// else's secret.
+func checkShareOwner(store KeyStore, s partyShare) error {
+	var saved struct {
+		ShareID *big.Int
+	}
+	if err := LoadShareFrom(store, s.id, &saved); err != nil {
+		return err
+	}
+	if saved.ShareID == nil || s.shareID == nil || saved.ShareID.Cmp(s.shareID) != 0 {
+		return fmt.Errorf("%w: share file for %s holds share ID %v, expected %v",
+			ErrShareCorrupted, s.id, saved.ShareID, s.shareID)
+	}
+	return nil
+}
+
+// shareFile is where the share for partyID lives inside an output directory.
+func shareFile(dir, partyID string) string {
+	return filepath.Join(dir, partyID+".json")
+}
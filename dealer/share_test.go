@@ -0,0 +1,319 @@
+package dealer
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	edkeygen "github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// fakeShares returns a minimal share for each of ids, numbered from 1, with
+// a tag telling one deal's shares from another's.
+func fakeShares(tag string, ids ...string) []partyShare {
+	shares := make([]partyShare, len(ids))
+	for i, id := range ids {
+		shareID := big.NewInt(int64(i + 1))
+		shares[i] = partyShare{id: id, shareID: shareID, data: struct {
+			ShareID *big.Int
+			Tag     string
+		}{shareID, tag}}
+	}
+	return shares
+}
+
+// readShares returns the content of each party's share file in dir, or nil
+// for a missing one.
+func readShares(t *testing.T, dir string, ids ...string) map[string][]byte {
+	t.Helper()
+	got := map[string][]byte{}
+	for _, id := range ids {
+		bz, err := os.ReadFile(shareFile(dir, id))
+		if err != nil && !os.IsNotExist(err) {
+			t.Fatal(err)
+		}
+		got[id] = bz
+	}
+	return got
+}
+
+func TestPublishSharesRestoresReplacedFiles(t *testing.T) {
+	dir := t.TempDir()
+	ids := []string{"a", "b", "c"}
+	if err := publishShares(dir, fakeShares("first", ids...)); err != nil {
+		t.Fatal(err)
+	}
+	before := readShares(t, dir, ids...)
+
+	// d has no file yet and c can't be replaced: a rename onto a non-empty
+	// directory fails after a, b and d were already moved into place.
+	os.Remove(shareFile(dir, "c"))
+	if err := os.MkdirAll(filepath.Join(shareFile(dir, "c"), "blocker"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := publishShares(dir, fakeShares("second", "a", "b", "d", "c")); err == nil {
+		t.Fatal("expected the publish to fail")
+	}
+	after := readShares(t, dir, "a", "b", "d")
+	for _, id := range []string{"a", "b"} {
+		if !bytes.Equal(after[id], before[id]) {
+			t.Errorf("share %s was not restored", id)
+		}
+	}
+	if after["d"] != nil {
+		t.Error("new share d was left behind")
+	}
+
+	// A second run once the cause is gone goes through.
+	if err := os.RemoveAll(shareFile(dir, "c")); err != nil {
+		t.Fatal(err)
+	}
+	if err := publishShares(dir, fakeShares("second", "a", "b", "d", "c")); err != nil {
+		t.Fatal(err)
+	}
+	for id, bz := range readShares(t, dir, "a", "b", "c", "d") {
+		if !bytes.Contains(bz, []byte("second")) {
+			t.Errorf("share %s is not from the second run", id)
+		}
+	}
+}
+
+func TestRerunAfterFailedImport(t *testing.T) {
+	skipIfShort(t)
+	dir := t.TempDir()
+	cfg := testConfig(SchemeEDDSA, 1, 3)
+	cfg.OutputDir = dir
+	cfg.Timeout = 10 * time.Second
+	cfg.Deliver = func(from, to *tss.PartyID) bool { return to.Id != "signer3" }
+	if _, err := ImportEdDSAKey(cfg, testKey(t, cfg)); !errors.Is(err, ErrResharingTimeout) {
+		t.Fatalf("got %v, want ErrResharingTimeout", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("failed import left %d entries behind", len(entries))
+	}
+
+	cfg.Deliver = nil
+	cfg.Timeout = 0
+	res, err := ImportEdDSAKey(cfg, testKey(t, cfg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range cfg.Parties {
+		if _, err := os.Stat(shareFile(dir, p.ID)); err != nil {
+			t.Error(err)
+		}
+	}
+	if !res.Complete {
+		t.Fatal("rerun not marked complete")
+	}
+}
+
+// captureStdout runs f with os.Stdout redirected and returns what it wrote,
+// one entry per line.
+func captureStdout(t *testing.T, f func() error) ([]string, error) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	lines := make(chan []string)
+	go func() {
+		var got []string
+		sc := bufio.NewScanner(r)
+		sc.Buffer(nil, 1<<24)
+		for sc.Scan() {
+			got = append(got, sc.Text())
+		}
+		lines <- got
+	}()
+	err = f()
+	os.Stdout = stdout
+	w.Close()
+	return <-lines, err
+}
+
+func TestStdoutShareLines(t *testing.T) {
+	pub, priv, err := GenerateRecipientKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := []string{"a", "b", "c"}
+	for name, recipient := range map[string]string{"plain": "", "sealed": pub.String()} {
+		t.Run(name, func(t *testing.T) {
+			cfg := &ImportConfig{OutputFormat: OutputStdout, OutputRecipient: recipient}
+			lines, err := captureStdout(t, func() error {
+				return writeShares(cfg, fakeShares("stdout", ids...), Manifest{Threshold: 1})
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(lines) != len(ids)+1 {
+				t.Fatalf("got %d lines, want %d", len(lines), len(ids)+1)
+			}
+			var key *RecipientPrivKey
+			if recipient != "" {
+				key = &priv
+				var share struct{ Tag string }
+				if _, err := ReadShareLine(lines[0], nil, &share); err == nil {
+					t.Error("a sealed line parsed without the key")
+				}
+			}
+			for i, id := range ids {
+				var share struct {
+					ShareID *big.Int
+					Tag     string
+				}
+				got, err := ReadShareLine(lines[i], key, &share)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got != id || share.Tag != "stdout" || share.ShareID.Int64() != int64(i+1) {
+					t.Errorf("line %d: got %s %+v", i, got, share)
+				}
+			}
+			var manifest Manifest
+			if got, err := ReadShareLine(lines[len(ids)], key, &manifest); err != nil || got != ManifestID || manifest.Threshold != 1 {
+				t.Errorf("manifest line: got %s %+v (%v)", got, manifest, err)
+			}
+		})
+	}
+}
+
+func TestValidateOutputRecipient(t *testing.T) {
+	pub, _, err := GenerateRecipientKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, tc := range map[string]struct {
+		format, recipient string
+		ok                bool
+	}{
+		"stdout":     {OutputStdout, pub.String(), true},
+		"files":      {OutputFiles, pub.String(), false},
+		"not base64": {OutputStdout, "not a key!", false},
+		"short key":  {OutputStdout, pub.String()[:20], false},
+	} {
+		cfg := testConfig(SchemeEDDSA, 1, 3)
+		cfg.OutputFormat, cfg.OutputRecipient = tc.format, tc.recipient
+		if err := cfg.Validate(); (err == nil) != tc.ok {
+			t.Errorf("%s: got %v", name, err)
+		}
+	}
+}
+
+func TestImportToSealedStdout(t *testing.T) {
+	skipIfShort(t)
+	pub, priv, err := GenerateRecipientKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := testConfig(SchemeEDDSA, 1, 3)
+	cfg.OutputFormat, cfg.OutputRecipient = OutputStdout, pub.String()
+	var logged bytes.Buffer
+	cfg.Logger = log.New(&logged, "", 0)
+	key := testKey(t, cfg)
+	want := new(big.Int).Set(key)
+	lines, err := captureStdout(t, func() error {
+		_, err := ImportEdDSAKey(cfg, key)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var shares []edkeygen.LocalPartySaveData
+	for _, line := range lines {
+		if strings.HasPrefix(line, ManifestID+" ") {
+			continue
+		}
+		var share edkeygen.LocalPartySaveData
+		if _, err := ReadShareLine(line, &priv, &share); err != nil {
+			t.Fatal(err)
+		}
+		shares = append(shares, share)
+	}
+	if len(shares) != cfg.PartyCount {
+		t.Fatalf("got %d shares on stdout, want %d", len(shares), cfg.PartyCount)
+	}
+	got, err := ReconstructEdDSAKey(shares)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Cmp(want) != 0 {
+		t.Fatal("shares from stdout reconstruct a different key")
+	}
+	if logged.Len() == 0 {
+		t.Fatal("nothing was logged")
+	}
+	for _, s := range shares {
+		if strings.Contains(logged.String(), s.Xi.String()) {
+			t.Fatalf("the log holds share %v's secret", s.ShareID)
+		}
+	}
+}
+
+func TestPublishSharesFailingMidWriteLeavesNothing(t *testing.T) {
+	dir := t.TempDir()
+	shares := fakeShares("deal", "a", "b", "c", "d", "e")
+	// The third share can't be serialized, so writing stops after two files.
+	shares[2].data = make(chan int)
+	if err := publishShares(dir, shares); err == nil {
+		t.Fatal("expected the publish to fail")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		t.Errorf("failed write left %s behind", e.Name())
+	}
+}
+
+// swappingStore is a KeyStore that files a's share under b and b's under a.
+type swappingStore struct {
+	KeyStore
+	a, b string
+}
+
+func (s swappingStore) PutShare(id string, data []byte) error {
+	switch id {
+	case s.a:
+		id = s.b
+	case s.b:
+		id = s.a
+	}
+	return s.KeyStore.PutShare(id, data)
+}
+
+func TestMisattributedShareFails(t *testing.T) {
+	t.Run("cross-wired results", func(t *testing.T) {
+		dir := t.TempDir()
+		shares := fakeShares("deal", "a", "b", "c")
+		shares[0].data, shares[1].data = shares[1].data, shares[0].data
+		if err := publishShares(dir, shares); !errors.Is(err, ErrShareCorrupted) {
+			t.Fatalf("got %v, want ErrShareCorrupted", err)
+		}
+		if got := readShares(t, dir, "a", "b", "c"); got["a"] != nil || got["b"] != nil || got["c"] != nil {
+			t.Fatal("misattributed shares were published")
+		}
+	})
+	t.Run("cross-wired store", func(t *testing.T) {
+		store := swappingStore{KeyStore: NewMemoryKeyStore(), a: "a", b: "c"}
+		if err := putShares(store, fakeShares("deal", "a", "b", "c")); !errors.Is(err, ErrShareCorrupted) {
+			t.Fatalf("got %v, want ErrShareCorrupted", err)
+		}
+	})
+}
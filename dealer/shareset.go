@@ -0,0 +1,131 @@
+package dealer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+
+	tsscrypto "github.com/bnb-chain/tss-lib/v2/crypto"
+	eckeygen "github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+)
+
+// UpdateShareSet merges updated shares, keyed by party id and e.g. from
+// ReplaceSigner, into the share set in store. The shares are keyed by id
+// because save data doesn't name its party, and a replacement signer has no
+// stored share to match it by. Every updated share must
+// belong to the stored group: same public key and share IDs, and, for a
+// party that already has a stored share, the same share ID. A party with no
+// stored share, such as one replacing a lost signer, gets its share added,
+// so at least one updated party must already have one to check against.
+// Together the updated shares must agree on the group's public commitments.
+//
+// Only shares whose content changes are written. In a FileKeyStore either
+// all of them are replaced or, if replacing fails part way, the ones
+// already replaced are restored; other stores are written one share at a
+// time. A replaced signer's share is left under its old id: it no longer
+// combines with the new shares, but should still be deleted.
+func UpdateShareSet(store KeyStore, updated map[string]eckeygen.LocalPartySaveData) error {
+	ids := make([]string, 0, len(updated))
+	for id := range updated {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	stored := make(map[string]eckeygen.LocalPartySaveData, len(ids))
+	var ref *eckeygen.LocalPartySaveData
+	for _, id := range ids {
+		var s eckeygen.LocalPartySaveData
+		err := LoadShareFrom(store, id, &s)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if s.ShareID == nil || s.ECDSAPub == nil {
+			return fmt.Errorf("%w: stored share for %s is not an ECDSA share", ErrShareCorrupted, id)
+		}
+		if ref == nil {
+			ref = &s
+		} else if !s.ECDSAPub.Equals(ref.ECDSAPub) || !sameKs(s.Ks, ref.Ks) {
+			return fmt.Errorf("%w: stored shares for %s and %s are for different groups", ErrShareCorrupted, ids[0], id)
+		}
+		stored[id] = s
+	}
+	if ref == nil {
+		return fmt.Errorf("%w: none of the updated parties has a stored share to check the update against", ErrInvalidConfig)
+	}
+
+	var changed []partyShare
+	owners := make(map[string]string, len(ids))
+	shares := make([]eckeygen.LocalPartySaveData, 0, len(ids))
+	for _, id := range ids {
+		u := updated[id]
+		if u.ShareID == nil || u.ECDSAPub == nil {
+			return fmt.Errorf("%w: updated share for %s has no share ID or public key", ErrShareCorrupted, id)
+		}
+		if !u.ECDSAPub.Equals(ref.ECDSAPub) {
+			return fmt.Errorf("%w: updated share for %s would change the group's public key", ErrShareCorrupted, id)
+		}
+		if !sameKs(u.Ks, ref.Ks) {
+			return fmt.Errorf("%w: updated share for %s is for a different group", ErrShareCorrupted, id)
+		}
+		if owner, ok := owners[u.ShareID.String()]; ok {
+			return fmt.Errorf("%w: %s and %s were both given share ID %s", ErrInvalidConfig, owner, id, u.ShareID)
+		}
+		owners[u.ShareID.String()] = id
+		shares = append(shares, u)
+		pos, _ := shareIndex(u.ShareID, ref.Ks)
+		if pos < 0 {
+			return fmt.Errorf("%w: share ID %s of %s is not part of the group", ErrInvalidConfig, u.ShareID, id)
+		}
+		if u.Xi == nil || pos >= len(u.BigXj) || !tsscrypto.ScalarBaseMult(u.ECDSAPub.Curve(), u.Xi).Equals(u.BigXj[pos]) {
+			return fmt.Errorf("%w: updated share for %s doesn't match its public commitment", ErrShareCorrupted, id)
+		}
+
+		s, ok := stored[id]
+		if !ok {
+			changed = append(changed, partyShare{id: id, shareID: u.ShareID, data: u})
+			continue
+		}
+		if u.ShareID.Cmp(s.ShareID) != 0 {
+			return fmt.Errorf("%w: updated share for %s has share ID %s but its stored share has %s",
+				ErrInvalidConfig, id, u.ShareID, s.ShareID)
+		}
+		same, err := sameShareData(s, u)
+		if err != nil {
+			return err
+		}
+		if !same {
+			changed = append(changed, partyShare{id: id, shareID: u.ShareID, data: u})
+		}
+	}
+	if _, err := ComputePublicVerification(shares); err != nil {
+		return err
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	if files, ok := store.(FileKeyStore); ok {
+		return publishShares(files.Dir, changed)
+	}
+	return putShares(store, changed)
+}
+
+// sameShareData reports whether a and b serialize to the same share file.
+func sameShareData(a, b eckeygen.LocalPartySaveData) (bool, error) {
+	abz, err := json.Marshal(a)
+	if err != nil {
+		return false, err
+	}
+	defer wipeBytes(abz)
+	bbz, err := json.Marshal(b)
+	if err != nil {
+		return false, err
+	}
+	defer wipeBytes(bbz)
+	return bytes.Equal(abz, bbz), nil
+}
@@ -0,0 +1,165 @@
+package dealer
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"os"
+	"sync"
+	"testing"
+
+	eckeygen "github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// countingStore is a KeyStore that records which ids were written.
+type countingStore struct {
+	KeyStore
+	mu  sync.Mutex
+	put []string
+}
+
+func (s *countingStore) PutShare(id string, data []byte) error {
+	s.mu.Lock()
+	s.put = append(s.put, id)
+	s.mu.Unlock()
+	return s.KeyStore.PutShare(id, data)
+}
+
+// lostShareSet returns a three-signer group whose signer2 share is lost,
+// stored in store, along with every share.
+func lostShareSet(t *testing.T, store KeyStore) []eckeygen.LocalPartySaveData {
+	t.Helper()
+	shares := shamirShares(t, tss.S256(), big.NewInt(0x5e7), 1, 1, 2, 3)
+	for id, i := range map[string]int{"signer1": 0, "signer3": 2} {
+		if err := SaveShareTo(store, id, shares[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return shares
+}
+
+func TestUpdateShareSetRestoresLostShare(t *testing.T) {
+	store := &countingStore{KeyStore: NewMemoryKeyStore()}
+	shares := lostShareSet(t, store)
+	store.put = nil
+
+	err := UpdateShareSet(store, map[string]eckeygen.LocalPartySaveData{
+		"signer1":  shares[0],
+		"signer2b": shares[1],
+		"signer3":  shares[2],
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(store.put) != 1 || store.put[0] != "signer2b" {
+		t.Fatalf("wrote %v, want only signer2b", store.put)
+	}
+	var got eckeygen.LocalPartySaveData
+	if err := LoadShareFrom(store, "signer2b", &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.ShareID.Cmp(big.NewInt(2)) != 0 {
+		t.Fatalf("restored share has share ID %s", got.ShareID)
+	}
+}
+
+func TestUpdateShareSetWritesOneFile(t *testing.T) {
+	dir := t.TempDir()
+	shares := lostShareSet(t, FileKeyStore{Dir: dir})
+	before := readShares(t, dir, "signer1", "signer3")
+
+	err := UpdateShareSet(FileKeyStore{Dir: dir}, map[string]eckeygen.LocalPartySaveData{
+		"signer1":  shares[0],
+		"signer2b": shares[1],
+		"signer3":  shares[2],
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	after := readShares(t, dir, "signer1", "signer2b", "signer3")
+	for _, id := range []string{"signer1", "signer3"} {
+		if !bytes.Equal(before[id], after[id]) {
+			t.Errorf("%s was rewritten", id)
+		}
+	}
+	if after["signer2b"] == nil {
+		t.Fatal("signer2b was not written")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("%d entries in the share directory, want 3", len(entries))
+	}
+}
+
+func TestUpdateShareSetFromReplaceSigner(t *testing.T) {
+	skipIfShort(t)
+	cfg := testConfig(SchemeECDSA, 1, 3)
+	shares, key := dealECDSA(t, cfg)
+	store := &countingStore{KeyStore: NewMemoryKeyStore()}
+	for _, i := range []int{0, 2} {
+		if err := SaveShareTo(store, cfg.Parties[i].ID, shares[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	store.put = nil
+
+	updated, err := ReplaceSigner(without(shares, 2), big.NewInt(2),
+		PartyConfig{ID: "signer2b", Moniker: "Signer2b", Index: 2},
+		RefreshConfig{Threshold: 1, PreParamsDir: testPreParamsDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The survivors reshare under share IDs aliased by the curve order; the
+	// update must see the group's real share IDs or it won't match the
+	// stored shares.
+	err = UpdateShareSet(store, map[string]eckeygen.LocalPartySaveData{
+		"signer1":  updated[0],
+		"signer2b": updated[1],
+		"signer3":  updated[2],
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Every share is redrawn, so every file is rewritten.
+	if len(store.put) != 3 {
+		t.Fatalf("wrote %v, want all three signers", store.put)
+	}
+	stored := make([]eckeygen.LocalPartySaveData, 3)
+	for i, id := range []string{"signer1", "signer2b", "signer3"} {
+		if err := LoadShareFrom(store, id, &stored[i]); err != nil {
+			t.Fatal(err)
+		}
+		if stored[i].ShareID.Cmp(big.NewInt(int64(i+1))) != 0 {
+			t.Errorf("%s holds share ID %s", id, stored[i].ShareID)
+		}
+	}
+	if err := VerifyAllQuorums(stored, 1, key); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUpdateShareSetRejects(t *testing.T) {
+	store := NewMemoryKeyStore()
+	shares := lostShareSet(t, store)
+	other := shamirShares(t, tss.S256(), big.NewInt(0xbad), 1, 1, 2, 3)
+	outside := shamirShares(t, tss.S256(), big.NewInt(0x5e7), 1, 1, 2, 3, 4)
+
+	for name, tc := range map[string]struct {
+		updated map[string]eckeygen.LocalPartySaveData
+		want    error
+	}{
+		"another key":     {map[string]eckeygen.LocalPartySaveData{"signer1": other[0]}, ErrShareCorrupted},
+		"another group":   {map[string]eckeygen.LocalPartySaveData{"signer1": shares[0], "signer4": outside[3]}, ErrShareCorrupted},
+		"moved share":     {map[string]eckeygen.LocalPartySaveData{"signer1": shares[1]}, ErrInvalidConfig},
+		"shared share ID": {map[string]eckeygen.LocalPartySaveData{"signer1": shares[0], "signer1b": shares[0]}, ErrInvalidConfig},
+		"nothing stored":  {map[string]eckeygen.LocalPartySaveData{"signer2b": shares[1]}, ErrInvalidConfig},
+	} {
+		if err := UpdateShareSet(store, tc.updated); !errors.Is(err, tc.want) {
+			t.Errorf("%s: got %v, want %v", name, err, tc.want)
+		}
+	}
+}
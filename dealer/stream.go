@@ -0,0 +1,49 @@
+package dealer
+
+import (
+	"math/big"
+
+	eckeygen "github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+)
+
+// SignerResult is one signer's save data, as streamed by
+// ImportECDSAKeyStream.
+type SignerResult struct {
+	PartyID string
+	Data    eckeygen.LocalPartySaveData
+}
+
+// ImportECDSAKeyStream runs ImportECDSAKey in the background and sends each
+// signer's save data on the result channel as soon as that signer finishes,
+// for UIs that show the deal progressing. The result channel is closed when
+// the import ends; a fatal error is then sent on the error channel, which is
+// closed after.
+//
+// Streamed shares are not verified yet: treat them as final only once the
+// error channel closes without an error.
+func ImportECDSAKeyStream(cfg *ImportConfig, plaintextKey *big.Int) (<-chan SignerResult, <-chan error) {
+	return importECDSAKeyStream(cfg, plaintextKey, nil, nil)
+}
+
+// importECDSAKeyStream is ImportECDSAKeyStream, giving up with ErrClosed once
+// cancel is closed and calling finished, when set, after the import ends.
+func importECDSAKeyStream(cfg *ImportConfig, plaintextKey *big.Int, cancel <-chan struct{}, finished func()) (<-chan SignerResult, <-chan error) {
+	// Room for every signer, so a slow reader never holds up the import and
+	// its timeout.
+	resultCh := make(chan SignerResult, len(cfg.Parties))
+	errCh := make(chan error, 1)
+	go func() {
+		if finished != nil {
+			defer finished()
+		}
+		defer close(errCh)
+		_, err := importECDSAKey(cfg, plaintextKey, func(r SignerResult) {
+			resultCh <- r
+		}, cancel)
+		close(resultCh)
+		if err != nil {
+			errCh <- err
+		}
+	}()
+	return resultCh, errCh
+}
@@ -0,0 +1,71 @@
+package dealer
+
+import (
+	"testing"
+	"time"
+)
+
+// heldStore is a KeyStore whose writes wait until release is closed.
+type heldStore struct {
+	KeyStore
+	writing chan struct{}
+	release chan struct{}
+}
+
+func (s *heldStore) PutShare(id string, data []byte) error {
+	select {
+	case s.writing <- struct{}{}:
+	default:
+	}
+	<-s.release
+	return s.KeyStore.PutShare(id, data)
+}
+
+func TestImportECDSAKeyStreamIsIncremental(t *testing.T) {
+	skipIfShort(t)
+	cfg := testConfig(SchemeECDSA, 1, 3)
+	store := &heldStore{KeyStore: NewMemoryKeyStore(), writing: make(chan struct{}, 1), release: make(chan struct{})}
+	cfg.Store = store
+	results, errs := ImportECDSAKeyStream(cfg, testKey(t, cfg))
+
+	// The import can't finish while its shares can't be stored, so every
+	// result read here was sent before the import was over.
+	seen := map[string]bool{}
+	for len(seen) < cfg.PartyCount {
+		select {
+		case r, ok := <-results:
+			if !ok {
+				t.Fatalf("stream closed after %d of %d results", len(seen), cfg.PartyCount)
+			}
+			if seen[r.PartyID] {
+				t.Fatalf("%s streamed twice", r.PartyID)
+			}
+			seen[r.PartyID] = true
+		case <-time.After(cfg.Timeout):
+			t.Fatalf("only %d of %d results streamed", len(seen), cfg.PartyCount)
+		}
+	}
+	select {
+	case <-store.writing:
+	case <-time.After(cfg.Timeout):
+		t.Fatal("import never got to storing the shares")
+	}
+	select {
+	case err := <-errs:
+		t.Fatalf("import ended (%v) before its shares were stored", err)
+	default:
+	}
+
+	close(store.release)
+	if _, ok := <-results; ok {
+		t.Fatal("stream sent more than one result per signer")
+	}
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range cfg.Parties {
+		if _, err := store.GetShare(p.ID); err != nil {
+			t.Errorf("%s: %v", p.ID, err)
+		}
+	}
+}
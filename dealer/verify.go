@@ -0,0 +1,116 @@
+package dealer
+
+import (
+	"fmt"
+	"math/big"
+
+	tsscrypto "github.com/bnb-chain/tss-lib/v2/crypto"
+	eckeygen "github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+)
+
+// PublicShare is the public half of a signer's share: its share ID (the
+// Shamir x-coordinate) and the commitment X_j = x_j·G to its secret share.
+type PublicShare struct {
+	ShareID *big.Int
+	BigXj   *tsscrypto.ECPoint
+}
+
+// PublicShares lists the public commitments to every signer's share as
+// recorded in a single save data. They are safe to hand to auditors.
+func PublicShares(save eckeygen.LocalPartySaveData) []PublicShare {
+	shares := make([]PublicShare, len(save.Ks))
+	for j, k := range save.Ks {
+		shares[j] = PublicShare{ShareID: k, BigXj: save.BigXj[j]}
+	}
+	return shares
+}
+
+// ComputePublicVerification proves a deal is consistent using only public
+// data: it checks every share agrees on the public commitments, recombines
+// them by Lagrange interpolation in the exponent and returns the resulting
+// public key, or an error if it doesn't match the stored ECDSAPub.
+func ComputePublicVerification(results []eckeygen.LocalPartySaveData) (*tsscrypto.ECPoint, error) {
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no shares to verify")
+	}
+	ref := results[0]
+	if ref.ECDSAPub == nil {
+		return nil, fmt.Errorf("%w: share %s has no public key", ErrShareCorrupted, ref.ShareID)
+	}
+	if len(ref.Ks) == 0 || len(ref.Ks) != len(ref.BigXj) {
+		return nil, fmt.Errorf("%w: share %s has %d share IDs but %d commitments", ErrShareCorrupted, ref.ShareID, len(ref.Ks), len(ref.BigXj))
+	}
+	for _, r := range results[1:] {
+		if !r.ECDSAPub.Equals(ref.ECDSAPub) {
+			return nil, fmt.Errorf("%w: shares %s and %s disagree on the public key", ErrShareCorrupted, ref.ShareID, r.ShareID)
+		}
+		if !samePublicShares(PublicShares(r), PublicShares(ref)) {
+			return nil, fmt.Errorf("%w: shares %s and %s disagree on the public commitments", ErrShareCorrupted, ref.ShareID, r.ShareID)
+		}
+	}
+
+	// Interpolating through all n commitments recovers the constant term
+	// whatever the threshold, since the dealt polynomial has degree t < n.
+	curve := ref.ECDSAPub.Curve()
+	var pub *tsscrypto.ECPoint
+	for j := range ref.Ks {
+		lambda, err := lagrangeCoefficient(j, ref.Ks, curve.Params().N)
+		if err != nil {
+			return nil, err
+		}
+		term := ref.BigXj[j].ScalarMult(lambda)
+		if pub == nil {
+			pub = term
+			continue
+		}
+		if pub, err = pub.Add(term); err != nil {
+			return nil, fmt.Errorf("failed to combine commitments: %v", err)
+		}
+	}
+	if !pub.Equals(ref.ECDSAPub) {
+		return nil, fmt.Errorf("%w: commitments recombine to (%s, %s), not the stored public key (%s, %s)",
+			ErrShareCorrupted, pub.X(), pub.Y(), ref.ECDSAPub.X(), ref.ECDSAPub.Y())
+	}
+	return pub, nil
+}
+
+// lagrangeCoefficient is the Lagrange basis polynomial for xs[j] evaluated at
+// zero, modulo the group order n.
+func lagrangeCoefficient(j int, xs []*big.Int, n *big.Int) (*big.Int, error) {
+	return lagrangeCoefficientAt(j, xs, big.NewInt(0), n)
+}
+
+// lagrangeCoefficientAt is the Lagrange basis polynomial for xs[j] evaluated
+// at x, modulo the group order n.
+func lagrangeCoefficientAt(j int, xs []*big.Int, x, n *big.Int) (*big.Int, error) {
+	num, den := big.NewInt(1), big.NewInt(1)
+	for m, xm := range xs {
+		if m == j {
+			continue
+		}
+		diff := new(big.Int).Sub(xs[j], xm)
+		diff.Mod(diff, n)
+		if diff.Sign() == 0 {
+			return nil, fmt.Errorf("share ID %s appears more than once", xm)
+		}
+		num.Mul(num, new(big.Int).Sub(x, xm)).Mod(num, n)
+		den.Mul(den, diff).Mod(den, n)
+	}
+	inv := new(big.Int).ModInverse(den, n)
+	if inv == nil {
+		return nil, fmt.Errorf("share IDs are not invertible modulo the group order")
+	}
+	return num.Mul(num, inv).Mod(num, n), nil
+}
+
+func samePublicShares(a, b []PublicShare) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].ShareID.Cmp(b[i].ShareID) != 0 || !a[i].BigXj.Equals(b[i].BigXj) {
+			return false
+		}
+	}
+	return true
+}
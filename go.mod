@@ -6,6 +6,8 @@ require (
 	github.com/bnb-chain/tss-lib/v2 v2.0.2
 	github.com/ethereum/go-ethereum v1.16.1
 	github.com/ipfs/go-log v1.0.5
+	golang.org/x/crypto v0.36.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -27,7 +29,6 @@ require (
 	github.com/pkg/errors v0.9.1 // indirect
 	go.uber.org/multierr v1.11.0 // indirect
 	go.uber.org/zap v1.27.0 // indirect
-	golang.org/x/crypto v0.36.0 // indirect
 	golang.org/x/sys v0.34.0 // indirect
 	google.golang.org/protobuf v1.34.2 // indirect
 )
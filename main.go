@@ -1,436 +1,236 @@
+// Command tss-lib-resharing deals a private key to a tss-lib signer group
+// by resharing it from a 1-of-1 importer group. The dealing itself lives in
+// package dealer.
 package main
 
 import (
+	"bufio"
+	"flag"
 	"fmt"
 	"log"
 	"math/big"
-	"sync"
-	"time"
+	"os"
+	"path/filepath"
+	"strings"
 
 	golog "github.com/ipfs/go-log"
 
-	tsscrypto "github.com/bnb-chain/tss-lib/v2/crypto"
-	eckeygen "github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
-	ecresharing "github.com/bnb-chain/tss-lib/v2/ecdsa/resharing"
-	edkeygen "github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
-	edresharing "github.com/bnb-chain/tss-lib/v2/eddsa/resharing"
-	"github.com/bnb-chain/tss-lib/v2/tss"
+	"github.com/tsimmons-zh/tss-lib-resharing/dealer"
 )
 
-// Types for channel communication
-type baseResult struct {
-	pid *tss.PartyID
-}
-type ecresult struct {
-	pid  *tss.PartyID
-	data eckeygen.LocalPartySaveData
-}
-
-type edresult struct {
-	pid  *tss.PartyID
-	data edkeygen.LocalPartySaveData
-}
-
-type msg struct {
-	from *tss.PartyID
-	data tss.Message
-}
-
 func main() {
-	// Enable debug logging
-	if err := golog.SetLogLevel("tss-lib", "debug"); err != nil {
-		panic(err)
+	if err := run(os.Args[1:]); err != nil {
+		log.Print(err)
+		os.Exit(1)
 	}
-
-	// runECDSAResharing()
-	runEDDSAResharing()
 }
 
-func runECDSAResharing() {
-	// 1) Define parties: importer (old group) + three co-signers (new group)
-	importerParty := tss.NewPartyID("importer", "Importer", big.NewInt(0))
-	signerParties := []*tss.PartyID{
-		tss.NewPartyID("signer1", "Signer1", big.NewInt(1)),
-		tss.NewPartyID("signer2", "Signer2", big.NewInt(2)),
-		tss.NewPartyID("signer3", "Signer3", big.NewInt(3)),
+// run runs the command line args, returning rather than exiting on failure
+// so deferred cleanup always happens.
+func run(args []string) error {
+	if len(args) > 0 && args[0] == "inspect" {
+		return runInspect(args[1:])
+	}
+	if len(args) > 0 && args[0] == "replace" {
+		return runReplace(args[1:])
+	}
+
+	fl := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	configPath := fl.String("config", "", "YAML or JSON group config (default: built-in 3-of-3 ed25519 demo group)")
+	outFormat := fl.String("out-format", "", "share output format: files or stdout (overrides the config)")
+	outRecipient := fl.String("out-recipient", "", "base64 X25519 public key to seal stdout share lines to (overrides the config)")
+	force := fl.Bool("force", false, "overwrite share files left in the output directory by an earlier run")
+	confirm := fl.Bool("confirm", false, "show the key and group and ask for confirmation before dealing")
+	yes := fl.Bool("yes", false, "skip the -confirm prompt, for automation")
+	recordPath := fl.String("record", "", "record every delivered protocol message to this file for replay")
+	verbose := fl.Bool("v", false, "log the deal's progress, every routed message and tss-lib's debug output to stderr")
+	fl.Parse(args)
+
+	if *verbose {
+		if err := golog.SetLogLevel("tss-lib", "debug"); err != nil {
+			return err
+		}
 	}
 
-	allOld := tss.NewPeerContext(
-		tss.SortPartyIDs([]*tss.PartyID{importerParty}),
-	)
-	allNew := tss.NewPeerContext(
-		tss.SortPartyIDs(signerParties),
-	)
-
-	curve := tss.S256() // secp256k1
-
-	// 2) Generate Paillier & ZK pre-params for each party
-	fmt.Println("Computing local PreParams")
-	preImp, _ := eckeygen.GeneratePreParams(1 * time.Minute)
-	preSigners := make([]*eckeygen.LocalPreParams, 3)
-	var err error
-	for i := range signerParties {
-		fmt.Printf("Computing local PreParams for signer %d\n", i)
-		preSigners[i], err = eckeygen.GeneratePreParams(1 * time.Minute)
-		if err != nil {
-			msg := fmt.Sprintf("failed to generate pre-params for signer %d: %v", i, err)
-			panic(msg)
+	cfg := dealer.DefaultImportConfig()
+	if *configPath != "" {
+		var err error
+		if cfg, err = dealer.LoadConfig(*configPath); err != nil {
+			return err
 		}
 	}
-	fmt.Println("Finished computing local PreParams")
-
-	// Channels for messages and results
-
-	outCh := make(chan msg, 10)
-	signerEndCh := make(chan ecresult, 3)
-
-	// Build resharing parameters: old=1-of-1, new=3-of-3 (t+1=3 ⇒ t=2)
-	impParams := tss.NewReSharingParameters(
-		curve,
-		allOld, allNew,
-		importerParty,
-		1, 0,
-		3, 2)
-
-	// impParams.NoProofFac()
-	// impParams.NoProofMod()
-
-	// Importer’s save data with the full private key
-	plaintextKey := big.NewInt(0xff) // ← your ECDSA private key here
-	impSave := eckeygen.NewLocalPartySaveData(1)
-	impSave.LocalPreParams = *preImp
-	impSave.LocalSecrets = eckeygen.LocalSecrets{
-		Xi:      plaintextKey,
-		ShareID: importerParty.KeyInt(),
+	if *outFormat != "" {
+		cfg.OutputFormat = *outFormat
 	}
-	impSave.Ks[0] = importerParty.KeyInt()
-	impSave.BigXj[0] = tsscrypto.ScalarBaseMult(curve, plaintextKey)
-	impSave.ECDSAPub = impSave.BigXj[0]
-
-	impSave.NTildej[0] = preImp.NTildei
-	impSave.H1j[0] = preImp.H1i
-	impSave.H2j[0] = preImp.H2i
-	impSave.PaillierPKs[0] = &preImp.PaillierSK.PublicKey
-
-	// Set signer's resharing parameters
-	signerParams := make([]*tss.ReSharingParameters, 3)
-	for i, pid := range signerParties {
-		signerParams[i] = tss.NewReSharingParameters(curve, allOld, allNew,
-			pid, 1, 0, 3, 2)
-		// signerParams[i].NoProofFac()
-		// signerParams[i].NoProofMod()
+	if *outRecipient != "" {
+		cfg.OutputRecipient = *outRecipient
 	}
-
-	// Simple broadcast router: send each outgoing message to all other parties
-	partyMap := make(map[string]*ecresharing.LocalParty)
-	var importerPartyInstance *ecresharing.LocalParty
-	var signerPartyInstances [3]*ecresharing.LocalParty
-
-	// Create all parties
-	discardEndCh := make(chan ecresult, 1) // importer results can be ignored
-	importerPartyInstance = ecresharing.NewLocalParty(
-		impParams,
-		impSave,
-		makeOutCh(importerParty, outCh),
-		makeEcEndCh(importerParty, discardEndCh),
-	).(*ecresharing.LocalParty)
-	partyMap[importerParty.Id] = importerPartyInstance
-
-	for i, pid := range signerParties {
-		if pid.KeyInt().Sign() < 0 {
-			panic(fmt.Sprintf("Invalid PartyID: %s has negative index %s", pid.Moniker, pid.KeyInt().String()))
+	cfg.Force = *force
+	if *verbose {
+		cfg.Logger = log.New(os.Stderr, "", log.LstdFlags)
+	}
+	if *recordPath != "" {
+		f, err := os.OpenFile(*recordPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
 		}
-		fmt.Printf("PartyID: %s, Index: %s\n", pid.Moniker, pid.KeyInt().String())
-
-		signerSave := eckeygen.NewLocalPartySaveData(1)
-		signerSave.LocalPreParams = *preSigners[i]
-
-		signerSave.Ks[0] = importerParty.KeyInt()
-		signerSave.BigXj[0] = impSave.BigXj[0]
-		signerSave.NTildej[0] = preImp.NTildei
-		signerSave.H1j[0] = preImp.H1i
-		signerSave.H2j[0] = preImp.H2i
-		signerSave.PaillierPKs[0] = &preImp.PaillierSK.PublicKey
-
-		signerPartyInstances[i] = ecresharing.NewLocalParty(
-			signerParams[i],
-			signerSave,
-			makeOutCh(pid, outCh),
-			makeEcEndCh(pid, signerEndCh),
-		).(*ecresharing.LocalParty)
-		partyMap[pid.Id] = signerPartyInstances[i]
+		defer f.Close()
+		cfg.Recorder = dealer.RecordTo(f)
 	}
 
-	var wg sync.WaitGroup
-
-	// Launch each co-signer’s resharing party (they start with only pre-params)
-	for i, pid := range signerParties {
-		wg.Add(1)
-		go func(i int, pid *tss.PartyID) {
-			defer wg.Done()
-			if err := signerPartyInstances[i].Start(); err != nil {
-				panic(err)
-			}
-		}(i, pid)
+	plaintextKey := big.NewInt(0xff) // ← your private key here
+	if *confirm && !*yes {
+		if err := cfg.Validate(); err != nil {
+			return err
+		}
+		if err := dealer.ConfirmDeal(cfg, plaintextKey, os.Stdin, os.Stderr); err != nil {
+			return err
+		}
 	}
-
-	// Launch importer’s party
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		if err := importerPartyInstance.Start(); err != nil {
-			log.Fatalf("Importer resharing party failed: %v", err)
+	d := dealer.NewDealer(cfg)
+	defer d.Close()
+	switch cfg.Scheme {
+	case dealer.SchemeECDSA:
+		if _, err := d.ImportECDSAKey(plaintextKey); err != nil {
+			return fmt.Errorf("ECDSA resharing failed: %v", err)
+		}
+	case dealer.SchemeEDDSA:
+		if _, err := d.ImportEdDSAKey(plaintextKey); err != nil {
+			return fmt.Errorf("EDDSA resharing failed: %v", err)
 		}
-	}()
+	}
+	fmt.Fprintf(os.Stderr, "Dealt the key to %d signers.\n", cfg.PartyCount)
+	return nil
+}
 
-	go func() {
-		for m := range outCh {
-			payload, routing, err := m.data.WireBytes()
+// runInspect implements the inspect subcommand: print the metadata of each
+// share file named on the command line or, with -lines, of each share line
+// in the named captures of the stdout output.
+func runInspect(args []string) error {
+	fl := flag.NewFlagSet("inspect", flag.ExitOnError)
+	lines := fl.Bool("lines", false, "read share lines captured from -out-format stdout instead of share files (- reads stdin)")
+	keyPath := fl.String("key", "", "file holding the base64 X25519 private key the share lines were sealed to")
+	fl.Parse(args)
+	if fl.NArg() == 0 {
+		return fmt.Errorf("usage: inspect [-lines [-key <private key file>]] <share file>...")
+	}
+	if *keyPath != "" && !*lines {
+		return fmt.Errorf("-key only applies to -lines")
+	}
+	if !*lines {
+		for _, path := range fl.Args() {
+			store, id, err := shareLocation(path)
 			if err != nil {
-				log.Printf("Error serializing message from %s: %v", m.from.Id, err)
-				continue
+				return err
 			}
-			fmt.Printf(">>> %s sending message to all parties: %s\n", m.from.Id, m.data.Type())
-			for _, to := range routing.To {
-				if to.Id == m.from.Id {
-					fmt.Printf("Ignoring message from self: %s\n", m.from.Id)
-					continue
-				}
-				p := partyMap[to.Id]
-				if p == nil {
-					log.Printf("Party instance for %s not found", to.Id)
-					continue
-				}
-				ok, err := p.UpdateFromBytes(payload, m.from, routing.IsBroadcast)
-				if err != nil {
-					log.Printf("Error updating party %s with message from %s: %v", to.Id, m.from.Id, err)
-				}
-				if !ok {
-					log.Printf("Party %s could not process message from %s: %v", to.Id, m.from.Id, err)
-				}
-				fmt.Printf(">>> %s updated party %s with message\n", m.from.Id, to.Id)
+			info, err := dealer.InspectShare(store, id)
+			if err != nil {
+				return err
 			}
+			printShareInfo(path, info)
 		}
-	}()
-
-	// Collect each signer’s new save data (their individual share + proofs)
-	results := map[string]ecresult{}
-	for i := 0; i < 3; i++ {
-		r := <-signerEndCh
-		// fmt.Printf(">>> %s completed with result: %+v\n", r.pid.Id, r.data)
-		// Persist r.data securely for future signing
-		results[r.pid.Id] = r
+		return nil
 	}
 
-	wg.Wait()
-
-	// Add all the Xi to make sure they sum to importer's Xi
-	totalXi := big.NewInt(0)
-	for _, r := range results {
-		totalXi.Add(totalXi, r.data.LocalSecrets.Xi)
-		fmt.Printf(">>> %s completed with result: %+v\n", r.pid.Id, r.data)
-		fmt.Println("--------------------------------------------------------")
-		fmt.Println()
+	var priv *dealer.RecipientPrivKey
+	if *keyPath != "" {
+		bz, err := os.ReadFile(*keyPath)
+		if err != nil {
+			return err
+		}
+		key, err := dealer.ParseRecipientPrivKey(string(bz))
+		clear(bz)
+		if err != nil {
+			return err
+		}
+		priv = &key
 	}
-	totalXi.Mod(totalXi, curve.Params().N) // Ensure it fits in the curve order
-	// Verify it matches the importer's original key
-	if plaintextKey.Cmp(impSave.LocalSecrets.Xi) != 0 {
-		log.Fatalf("Total Xi %s does not match importer's Xi %s", totalXi, impSave.LocalSecrets.Xi)
+	for _, path := range fl.Args() {
+		if err := inspectLines(path, priv); err != nil {
+			return err
+		}
 	}
-	fmt.Println(">>> All signers completed successfully. Total Xi matches.")
+	return nil
 }
 
-func runEDDSAResharing() {
-	// 1) Define parties: importer (old group) + three co-signers (new group)
-	importerParty := tss.NewPartyID("importer", "Importer", big.NewInt(0))
-	signerParties := []*tss.PartyID{
-		tss.NewPartyID("signer1", "Signer1", big.NewInt(1)),
-		tss.NewPartyID("signer2", "Signer2", big.NewInt(2)),
-		tss.NewPartyID("signer3", "Signer3", big.NewInt(3)),
-	}
-
-	allOld := tss.NewPeerContext(
-		tss.SortPartyIDs([]*tss.PartyID{importerParty}),
-	)
-	allNew := tss.NewPeerContext(
-		tss.SortPartyIDs(signerParties),
-	)
-
-	curve := tss.Edwards() // ED25519
-
-	// Channels for messages and results
-	outCh := make(chan msg, 10)
-	signerEndCh := make(chan edresult, 3)
-
-	// Build resharing parameters: old=1-of-1, new=3-of-3 (t+1=3 ⇒ t=2)
-	impParams := tss.NewReSharingParameters(
-		curve,
-		allOld, allNew,
-		importerParty,
-		1, 0,
-		3, 2)
-
-	// impParams.NoProofFac()
-	// impParams.NoProofMod()
-
-	// Importer’s save data with the full private key
-	plaintextKey := big.NewInt(0xff) // ← your ECDSA private key here
-	impSave := edkeygen.NewLocalPartySaveData(1)
-	impSave.LocalSecrets = edkeygen.LocalSecrets{
-		Xi:      plaintextKey,
-		ShareID: importerParty.KeyInt(),
-	}
-	impSave.Ks[0] = importerParty.KeyInt()
-	impSave.BigXj[0] = tsscrypto.ScalarBaseMult(curve, plaintextKey)
-	impSave.EDDSAPub = impSave.BigXj[0]
-
-	// Set signer's resharing parameters
-	signerParams := make([]*tss.ReSharingParameters, 3)
-	for i, pid := range signerParties {
-		signerParams[i] = tss.NewReSharingParameters(curve, allOld, allNew,
-			pid, 1, 0, 3, 2)
-	}
-
-	// Simple broadcast router: send each outgoing message to all other parties
-	partyMap := make(map[string]*edresharing.LocalParty)
-	var importerPartyInstance *edresharing.LocalParty
-	var signerPartyInstances [3]*edresharing.LocalParty
-
-	// Create all parties
-	discardEndCh := make(chan edresult, 1) // importer results can be ignored
-	importerPartyInstance = edresharing.NewLocalParty(
-		impParams,
-		impSave,
-		makeOutCh(importerParty, outCh),
-		makeEdEndCh(importerParty, discardEndCh),
-	).(*edresharing.LocalParty)
-	partyMap[importerParty.Id] = importerPartyInstance
-
-	for i, pid := range signerParties {
-		if pid.KeyInt().Sign() < 0 {
-			panic(fmt.Sprintf("Invalid PartyID: %s has negative index %s", pid.Moniker, pid.KeyInt().String()))
+// inspectLines prints the metadata of every share line in path, skipping
+// the deal's manifest line.
+func inspectLines(path string, priv *dealer.RecipientPrivKey) error {
+	f := os.Stdin
+	if path != "-" {
+		var err error
+		if f, err = os.Open(path); err != nil {
+			return err
 		}
-		fmt.Printf("PartyID: %s, Index: %s\n", pid.Moniker, pid.KeyInt().String())
-
-		signerSave := edkeygen.NewLocalPartySaveData(1)
-
-		signerSave.Ks[0] = importerParty.KeyInt()
-		signerSave.BigXj[0] = impSave.BigXj[0]
-
-		signerPartyInstances[i] = edresharing.NewLocalParty(
-			signerParams[i],
-			signerSave,
-			makeOutCh(pid, outCh),
-			makeEdEndCh(pid, signerEndCh),
-		).(*edresharing.LocalParty)
-		partyMap[pid.Id] = signerPartyInstances[i]
-	}
-
-	var wg sync.WaitGroup
-
-	// Launch each co-signer’s resharing party (they start with only pre-params)
-	for i, pid := range signerParties {
-		wg.Add(1)
-		go func(i int, pid *tss.PartyID) {
-			defer wg.Done()
-			if err := signerPartyInstances[i].Start(); err != nil {
-				panic(err)
-			}
-		}(i, pid)
-	}
-
-	// Launch importer’s party
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		if err := importerPartyInstance.Start(); err != nil {
-			log.Fatalf("Importer resharing party failed: %v", err)
+		defer f.Close()
+	}
+	sc := bufio.NewScanner(f)
+	sc.Buffer(nil, 1<<24)
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, dealer.ManifestID+" ") {
+			continue
 		}
-	}()
-
-	go func() {
-		for m := range outCh {
-			payload, routing, err := m.data.WireBytes()
-			if err != nil {
-				log.Printf("Error serializing message from %s: %v", m.from.Id, err)
-				continue
-			}
-			fmt.Printf(">>> %s sending message to all parties: %s\n", m.from.Id, m.data.Type())
-			for _, to := range routing.To {
-				if to.Id == m.from.Id {
-					fmt.Printf("Ignoring message from self: %s\n", m.from.Id)
-					continue
-				}
-				p := partyMap[to.Id]
-				if p == nil {
-					log.Printf("Party instance for %s not found", to.Id)
-					continue
-				}
-				ok, err := p.UpdateFromBytes(payload, m.from, routing.IsBroadcast)
-				if err != nil {
-					log.Printf("Error updating party %s with message from %s: %v", to.Id, m.from.Id, err)
-				}
-				if !ok {
-					log.Printf("Party %s could not process message from %s: %v", to.Id, m.from.Id, err)
-				}
-				fmt.Printf(">>> %s updated party %s with message\n", m.from.Id, to.Id)
-			}
+		id, info, err := dealer.InspectShareLine(line, priv)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
 		}
-	}()
-
-	// Collect each signer’s new save data (their individual share + proofs)
-	results := map[string]edresult{}
-	for i := 0; i < 3; i++ {
-		r := <-signerEndCh
-		// fmt.Printf(">>> %s completed with result: %+v\n", r.pid.Id, r.data)
-		// Persist r.data securely for future signing
-		results[r.pid.Id] = r
+		printShareInfo(id, info)
 	}
+	return sc.Err()
+}
 
-	wg.Wait()
-
-	// Add all the Xi to make sure they sum to importer's Xi
-	totalXi := big.NewInt(0)
-	for _, r := range results {
-		totalXi.Add(totalXi, r.data.LocalSecrets.Xi)
-		fmt.Printf(">>> %s completed with result: %+v\n", r.pid.Id, r.data)
-		fmt.Println("--------------------------------------------------------")
-		fmt.Println()
+// printShareInfo prints a share's metadata under the name it was read from.
+func printShareInfo(name string, info *dealer.ShareInfo) {
+	fmt.Printf("%s\n", name)
+	fmt.Printf("  scheme:      %s\n", info.Scheme)
+	fmt.Printf("  curve:       %s\n", info.Curve)
+	fmt.Printf("  share id:    %s\n", info.ShareID)
+	fmt.Printf("  party index: %d of %d\n", info.PartyIndex, info.PartyCount)
+	fmt.Printf("  public key:  (%s, %s)\n", info.PublicKey.X(), info.PublicKey.Y())
+	if info.Address != "" {
+		fmt.Printf("  address:     %s\n", info.Address)
 	}
-	totalXi.Mod(totalXi, curve.Params().N) // Ensure it fits in the curve order
-	// Verify it matches the importer's original key
-	if plaintextKey.Cmp(impSave.LocalSecrets.Xi) != 0 {
-		log.Fatalf("Total Xi %s does not match importer's Xi %s", totalXi, impSave.LocalSecrets.Xi)
-	}
-	fmt.Println(">>> All signers completed successfully. Total Xi matches.")
 }
 
-// Helpers to wrap channels with party IDs
-func makeOutCh(pid *tss.PartyID, outCh chan msg) chan tss.Message {
-	ch := make(chan tss.Message, 10)
-	go func() {
-		for m := range ch {
-			outCh <- msg{from: pid, data: m}
-		}
-	}()
-	return ch
-}
-func makeEcEndCh(pid *tss.PartyID, endCh chan ecresult) chan *eckeygen.LocalPartySaveData {
-	ch := make(chan *eckeygen.LocalPartySaveData, 1)
-	go func() {
-		for sd := range ch {
-			endCh <- ecresult{pid: pid, data: *sd}
-		}
-	}()
-	return ch
+// runReplace implements the replace subcommand: reshare a group's ECDSA
+// shares in its output directory so a new signer takes over from a lost one.
+func runReplace(args []string) error {
+	fl := flag.NewFlagSet("replace", flag.ExitOnError)
+	configPath := fl.String("config", "", "YAML or JSON group config whose output directory holds the shares")
+	lostID := fl.String("lost", "", "id of the signer whose share was lost")
+	newID := fl.String("new-id", "", "id of the signer taking over the lost signer's index")
+	newMoniker := fl.String("new-moniker", "", "moniker of the new signer (default: its id)")
+	fl.Parse(args)
+	if *configPath == "" || *lostID == "" || *newID == "" {
+		return fmt.Errorf("usage: replace -config <file> -lost <id> -new-id <id>")
+	}
+
+	cfg, err := dealer.LoadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	if cfg.OutputDir == "" {
+		return fmt.Errorf("config %s has no output_dir to read the shares from", *configPath)
+	}
+	moniker := *newMoniker
+	if moniker == "" {
+		moniker = *newID
+	}
+	newParty := dealer.PartyConfig{ID: *newID, Moniker: moniker}
+	if err := dealer.ReplaceStoredSigner(cfg, dealer.FileKeyStore{Dir: cfg.OutputDir}, *lostID, newParty); err != nil {
+		return fmt.Errorf("replacing %s failed: %v", *lostID, err)
+	}
+	fmt.Fprintf(os.Stderr, "Replaced %s with %s. Update %s to list %s in its place and delete %s's share.\n",
+		*lostID, *newID, *configPath, *newID, *lostID)
+	return nil
 }
 
-func makeEdEndCh(pid *tss.PartyID, endCh chan edresult) chan *edkeygen.LocalPartySaveData {
-	ch := make(chan *edkeygen.LocalPartySaveData, 1)
-	go func() {
-		for sd := range ch {
-			endCh <- edresult{pid: pid, data: *sd}
-		}
-	}()
-	return ch
+// shareLocation splits the path of a share file into the FileKeyStore
+// holding it and the party id it is stored under.
+func shareLocation(path string) (dealer.FileKeyStore, string, error) {
+	if filepath.Ext(path) != ".json" {
+		return dealer.FileKeyStore{}, "", fmt.Errorf("%s is not a share file (<party id>.json)", path)
+	}
+	return dealer.FileKeyStore{Dir: filepath.Dir(path)}, strings.TrimSuffix(filepath.Base(path), ".json"), nil
 }